@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web/analysis"
+)
+
+var (
+	analyzeInputDir, analyzeFormat, analyzeOutput string
+	analyzeTraits                                 bool
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [command]",
+	Short: "Analyze the metadata of a collection",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var analyzeRarityCmd = &cobra.Command{
+	Use:   "rarity",
+	Short: "compute the trait distribution and the rarity rank of every token",
+	Run: func(cmd *cobra.Command, args []string) {
+		metas, err := k0yote3web.ReadMetaDir(analyzeInputDir)
+		if err != nil {
+			panic(err)
+		}
+
+		report := analysis.Rarity(metas)
+
+		var w io.Writer = os.Stdout
+		if analyzeOutput != "" {
+			file, err := os.Create(analyzeOutput)
+			if err != nil {
+				panic(err)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		switch {
+		case analyzeFormat == "json":
+			err = report.WriteJSON(w)
+		case analyzeFormat == "csv" && analyzeTraits:
+			err = report.WriteTraitsCSV(w)
+		case analyzeFormat == "csv":
+			err = report.WriteRarityCSV(w)
+		default:
+			log.Fatalf("unknown format: [%s]\n", analyzeFormat)
+		}
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("analyzed tokens: [%d] trait values: [%d]\n", report.Tokens, len(report.Traits))
+	},
+}
+
+func init() {
+	analyzeCmd.PersistentFlags().StringVarP(&analyzeInputDir, "inputDir", "i", "", "the folder of metadata files, internal/meta by default")
+	analyzeCmd.PersistentFlags().StringVarP(&analyzeOutput, "output", "o", "", "file the report is written to, stdout by default")
+	analyzeRarityCmd.Flags().StringVarP(&analyzeFormat, "format", "f", "csv", "format of the report (csv, json)")
+	analyzeRarityCmd.Flags().BoolVar(&analyzeTraits, "traits", false, "write the trait distribution instead of the ranks as csv")
+
+	analyzeCmd.AddCommand(analyzeRarityCmd)
+}
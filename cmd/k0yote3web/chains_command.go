@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+var chainsCmd = &cobra.Command{
+	Use:   "chains",
+	Short: "List the chains that can be passed by name to chainRpcUrl",
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCHAIN ID\tCURRENCY\tTESTNET\tEXPLORER")
+		for _, chain := range k0yote3web.Chains() {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%t\t%s\n", chain.Name, chain.ChainID, chain.NativeCurrency.Symbol, chain.Testnet, chain.ExplorerURL)
+		}
+		if err := w.Flush(); err != nil {
+			panic(err)
+		}
+	},
+}
@@ -1,7 +1,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/spf13/viper"
 	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+	"golang.org/x/term"
 )
 
 var (
@@ -9,12 +19,30 @@ var (
 )
 
 func initSdk() {
+	keystore := viper.GetString("keystore")
+	passphrase := ""
+	if keystore != "" {
+		var err error
+		if passphrase, err = keystorePassphrase(); err != nil {
+			panic(err)
+		}
+	}
+
 	if sdk, err := k0yote3web.NewK0yote3WebSDK(
 		chainRpcUrl,
 		&k0yote3web.SDKOptions{
-			PrivateKey:        privateKey,
-			ThirdpartyProvier: k0yote3web.ThirdpartyProvider(thirdpartyProvider),
-			ApiKey:            apiKey,
+			PrivateKey:         privateKey,
+			KeystorePath:       keystore,
+			KeystorePassphrase: passphrase,
+			Mnemonic:           viper.GetString("mnemonic"),
+			DerivationPath:     viper.GetString("derivationPath"),
+			ThirdpartyProvier:  k0yote3web.ThirdpartyProvider(thirdpartyProvider),
+			ApiKey:             apiKey,
+			QuickNodeEndpoint:  quickNodeEndpoint,
+			Headers:            parseRpcHeaders(rpcHeaders),
+			FailoverRpcUrls:    viper.GetStringSlice("failoverRpcUrls"),
+			GasStrategy:        gasStrategyOptions(),
+			DryRun:             dryRun,
 		},
 	); err != nil {
 		panic(err)
@@ -23,16 +51,82 @@ func initSdk() {
 	}
 }
 
+// gasStrategyOptions returns the gas strategy set by the flags, nil to keep the node suggestions.
+func gasStrategyOptions() *k0yote3web.GasStrategyOptions {
+	if gasSpeed == "" && maxFeePerGas == "" && maxPriorityFeePerGas == "" {
+		return nil
+	}
+
+	opts := &k0yote3web.GasStrategyOptions{Speed: k0yote3web.GasSpeed(gasSpeed)}
+	if maxFeePerGas != "" {
+		opts.MaxFeePerGas = k0yote3web.ToWei(maxFeePerGas, 9)
+	}
+	if maxPriorityFeePerGas != "" {
+		opts.MaxPriorityFeePerGas = k0yote3web.ToWei(maxPriorityFeePerGas, 9)
+	}
+
+	return opts
+}
+
+func gwei(wei *big.Int) string {
+	return k0yote3web.FromWeiWithUnit(wei, k0yote3web.EtherUnitGWei).Text('f', 9)
+}
+
+// parseRpcHeaders parses headers given as "Name: value".
+func parseRpcHeaders(headers []string) http.Header {
+	parsed := http.Header{}
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			panic(fmt.Errorf("malformed rpc header, expected 'Name: value': %s", header))
+		}
+		parsed.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	return parsed
+}
+
+// keystorePassphrase reads the passphrase from KEYSTORE_PASSPHRASE, prompting on the terminal otherwise.
+func keystorePassphrase() (string, error) {
+	if passphrase, ok := os.LookupEnv("KEYSTORE_PASSPHRASE"); ok {
+		return passphrase, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Keystore passphrase: ")
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 func getDownload() (*k0yote3web.Download, error) {
 	if k0yote3webSDK == nil {
 		initSdk()
 	}
 
+	var node *k0yote3web.IPFSOptions
+	if ipfsNode != "" {
+		node = &k0yote3web.IPFSOptions{
+			ProviderType: k0yote3web.IPFSProvider(ipfsNode),
+			ProjectID:    projectID,
+			Secret:       secret,
+			ApiURL:       ipfsNodeApiURL,
+			Pin:          ipfsNodePin,
+		}
+	}
+
 	return k0yote3webSDK.GetDownload(
 		&k0yote3web.DownloadMetaOptions{
-			BaseURL:      baseURL,
-			StartTokenID: startTokenID,
-			EndTokenID:   endTokenID,
+			BaseURL:         baseURL,
+			StartTokenID:    startTokenID,
+			EndTokenID:      endTokenID,
+			ContractAddress: contractAddress,
+			IpfsGateways:    ipfsGateways,
+			ArweaveGateways: arweaveGateways,
+			IpfsNode:        node,
 		},
 	)
 }
@@ -49,6 +143,116 @@ func getRewrite() (*k0yote3web.MetaRewriter, error) {
 	)
 }
 
+func getReveal() (*k0yote3web.Reveal, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetReveal(
+		&k0yote3web.RevealOptions{
+			InputDir:       revealInputDir,
+			ImageDir:       revealImageDir,
+			PlaceholderDir: placeholderDir,
+			OutputDir:      revealOutputDir,
+		},
+	)
+}
+
+func getMetaDiffer() (*k0yote3web.MetaDiffer, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetMetaDiffer(diffBeforeDir, diffAfterDir)
+}
+
+func getMetaGenerator() (*k0yote3web.MetaGenerator, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetMetaGenerator(
+		&k0yote3web.GenerateMetaOptions{
+			CSVPath:      csvPath,
+			ImageDir:     generateImageDir,
+			OutputDir:    generateOutputDir,
+			ImageBaseURL: generateImageBaseURL,
+			StartTokenID: generateStartTokenID,
+		},
+	)
+}
+
+func getMetaExporter() (*k0yote3web.MetaExporter, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetMetaExporter(
+		&k0yote3web.ExportOptions{
+			InputDir: exportInputDir,
+			ImageDir: exportImageDir,
+			Format:   k0yote3web.ExportFormat(exportFormat),
+			ImageCID: exportImageCID,
+			FileHash: exportFileHash,
+		},
+	)
+}
+
+func getMetaValidator() (*k0yote3web.MetaValidator, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetMetaValidator(validateInputDir)
+}
+
+func getTxMonitor() (*k0yote3web.TxMonitor, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetTxMonitor(
+		&k0yote3web.TxMonitorOptions{
+			ReplaceAfter:  replaceAfter,
+			BumpPercent:   bumpPercent,
+			Confirmations: confirmations,
+		},
+	)
+}
+
+func getWallet() (*k0yote3web.Wallet, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	return k0yote3webSDK.GetWallet()
+}
+
+func getGasStrategy() (*k0yote3web.GasStrategy, error) {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	opts := gasStrategyOptions()
+	if opts == nil {
+		opts = &k0yote3web.GasStrategyOptions{}
+	}
+	return k0yote3webSDK.GetGasStrategy(opts)
+}
+
+func txOpts() *bind.TransactOpts {
+	if k0yote3webSDK == nil {
+		initSdk()
+	}
+
+	opts, err := k0yote3webSDK.GetTransactOpts(context.Background())
+	if err != nil {
+		panic(err)
+	}
+
+	return opts
+}
+
 func getIpfsUploader() (*k0yote3web.IpfsUploader, error) {
 	if k0yote3webSDK == nil {
 		initSdk()
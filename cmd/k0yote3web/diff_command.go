@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffBeforeDir, diffAfterDir, diffFormat string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two folders of metadata per token, internal/meta and internal/upload by default",
+	Run: func(cmd *cobra.Command, args []string) {
+		if diffFormat != "text" && diffFormat != "json" {
+			log.Fatalf("unknown format: [%s]\n", diffFormat)
+		}
+
+		differ, err := getMetaDiffer()
+		if err != nil {
+			panic(err)
+		}
+
+		diffs, err := differ.Diff()
+		if err != nil {
+			panic(err)
+		}
+
+		if diffFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(diffs); err != nil {
+				panic(err)
+			}
+		} else {
+			for _, diff := range diffs {
+				log.Println(diff)
+			}
+		}
+
+		log.Printf("tokens with differences: [%d]\n", len(diffs))
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffBeforeDir, "before", "b", "", "the folder of the metadata before, internal/meta by default")
+	diffCmd.Flags().StringVarP(&diffAfterDir, "after", "a", "", "the folder of the metadata after, internal/upload by default")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "format of the diff (text, json)")
+}
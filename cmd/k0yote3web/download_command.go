@@ -7,9 +7,15 @@ import (
 )
 
 var (
-	startTokenID int
-	endTokenID   int
-	baseURL      string
+	startTokenID    int
+	endTokenID      int
+	baseURL         string
+	contractAddress string
+	ipfsGateways    []string
+	arweaveGateways []string
+	ipfsNode        string
+	ipfsNodeApiURL  string
+	ipfsNodePin     bool
 )
 
 var downloadCmd = &cobra.Command{
@@ -59,6 +65,14 @@ func init() {
 	downloadCmd.PersistentFlags().IntVarP(&startTokenID, "sTokenId", "s", 0, "start from download token id")
 	downloadCmd.PersistentFlags().IntVarP(&endTokenID, "eTokenId", "e", 0, "end to download token id")
 	downloadCmd.PersistentFlags().StringVarP(&baseURL, "baseUrl", "b", "", "base URL to download")
+	downloadCmd.PersistentFlags().StringVarP(&contractAddress, "contract", "c", "", "ERC-721 contract to discover metadata URLs from tokenURI instead of baseUrl")
+	downloadCmd.PersistentFlags().StringSliceVar(&ipfsGateways, "ipfsGateway", nil, "ipfs gateways tried in order for ipfs:// urls, {cid} marks a subdomain gateway (e.g. https://{cid}.ipfs.dweb.link/)")
+	downloadCmd.PersistentFlags().StringSliceVar(&arweaveGateways, "arweaveGateway", nil, "arweave gateways tried in order for ar:// urls")
+	downloadCmd.PersistentFlags().StringVar(&ipfsNode, "ipfsNode", "", "ipfs api provider type (e.g. local or infura) ipfs:// urls are fetched from before the gateways")
+	downloadCmd.PersistentFlags().BoolVar(&ipfsNodePin, "ipfsNodePin", false, "pin the content fetched from the ipfs node on it")
+	downloadCmd.PersistentFlags().StringVar(&ipfsNodeApiURL, "ipfsNodeApiUrl", "", "kubo rpc url of the ipfs node, the default of the provider type when empty")
+	downloadCmd.PersistentFlags().StringVar(&projectID, "projectId", "", "api projectId for using infura")
+	downloadCmd.PersistentFlags().StringVar(&secret, "secret", "", "api secret for using infura")
 
 	downloadCmd.AddCommand(downloadMetasCmd)
 	downloadCmd.AddCommand(downloadImagesCmd)
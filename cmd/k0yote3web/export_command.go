@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportInputDir, exportImageDir, exportFormat, exportOutput string
+	exportImageCID, exportFileHash                             bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the metadata of a folder as a table, a row per token and a column per trait type",
+	Run: func(cmd *cobra.Command, args []string) {
+		exporter, err := getMetaExporter()
+		if err != nil {
+			panic(err)
+		}
+
+		var w io.Writer = os.Stdout
+		if exportOutput != "" {
+			file, err := os.Create(exportOutput)
+			if err != nil {
+				panic(err)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		count, err := exporter.Export(w)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("exported tokens: [%d]\n", count)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportInputDir, "inputDir", "i", "", "the folder of metadata files, internal/meta by default")
+	exportCmd.Flags().StringVar(&exportImageDir, "imageDir", "", "the folder of the images hashed with --fileHash, internal/image by default")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "format of the export (csv, jsonl)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file the export is written to, stdout by default")
+	exportCmd.Flags().BoolVar(&exportImageCID, "imageCid", false, "add the CID of ipfs image urls")
+	exportCmd.Flags().BoolVar(&exportFileHash, "fileHash", false, "add the sha256 of the downloaded image files")
+}
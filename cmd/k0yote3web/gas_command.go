@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+var opsFile string
+
+// operation is a transaction of the batch read by gas simulate, value is in ether
+type operation struct {
+	To    common.Address `json:"to"`
+	Data  hexutil.Bytes  `json:"data"`
+	Value string         `json:"value"`
+}
+
+var gasCmd = &cobra.Command{
+	Use:   "gas [command]",
+	Short: "Estimate transaction fees from the recent fee history",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var gasFeesCmd = &cobra.Command{
+	Use:   "fees",
+	Short: "print the slow, standard and fast fees",
+	Run: func(cmd *cobra.Command, args []string) {
+		strategy, err := getGasStrategy()
+		if err != nil {
+			panic(err)
+		}
+
+		estimate, err := strategy.Estimate(context.Background())
+		if err != nil {
+			panic(err)
+		}
+
+		if estimate.BaseFee != nil {
+			log.Printf("next base fee: [%s gwei]\n", gwei(estimate.BaseFee))
+		}
+		for _, speed := range []k0yote3web.GasSpeed{k0yote3web.GasSlow, k0yote3web.GasStandard, k0yote3web.GasFast} {
+			fees := estimate.Fees[speed]
+			if fees.GasPrice != nil {
+				log.Printf("%s gasPrice: [%s gwei]\n", speed, gwei(fees.GasPrice))
+				continue
+			}
+			log.Printf("%s maxPriorityFeePerGas: [%s gwei] maxFeePerGas: [%s gwei]\n", speed, gwei(fees.GasTipCap), gwei(fees.GasFeeCap))
+		}
+	},
+}
+
+var gasSimulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "estimate the total cost of sending a batch of transactions read from a JSON file",
+	Run: func(cmd *cobra.Command, args []string) {
+		strategy, err := getGasStrategy()
+		if err != nil {
+			panic(err)
+		}
+
+		b, err := os.ReadFile(opsFile)
+		if err != nil {
+			panic(err)
+		}
+		var ops []operation
+		if err := json.Unmarshal(b, &ops); err != nil {
+			panic(err)
+		}
+
+		from := k0yote3webSDK.GetSignerAddress()
+		calls := make([]ethereum.CallMsg, 0, len(ops))
+		for _, op := range ops {
+			to := op.To
+			call := ethereum.CallMsg{From: from, To: &to, Data: op.Data}
+			if op.Value != "" {
+				call.Value = k0yote3web.ToWeiR(op.Value)
+			}
+			calls = append(calls, call)
+		}
+
+		cost, err := strategy.SimulateBatch(context.Background(), calls)
+		if err != nil {
+			panic(err)
+		}
+
+		for i, op := range cost.Operations {
+			if op.Err != nil {
+				log.Printf("operation [%d] to: [%s] failed: [%v]\n", i, ops[i].To.Hex(), op.Err)
+				continue
+			}
+			log.Printf("operation [%d] to: [%s] gas: [%d]\n", i, ops[i].To.Hex(), op.Gas)
+		}
+		log.Printf("total gas: [%d]\n", cost.TotalGas)
+		for _, speed := range []k0yote3web.GasSpeed{k0yote3web.GasSlow, k0yote3web.GasStandard, k0yote3web.GasFast} {
+			log.Printf("%s expected cost: [%s] max cost: [%s]\n", speed, k0yote3web.ToEther(cost.ExpectedCost[speed]).Text('f', 18), k0yote3web.ToEther(cost.MaxCost[speed]).Text('f', 18))
+		}
+	},
+}
+
+func init() {
+	gasSimulateCmd.Flags().StringVarP(&opsFile, "operations", "f", "", `JSON file of transactions, e.g. [{"to": "0x..", "data": "0x..", "value": "0.1"}]`)
+
+	gasCmd.AddCommand(gasFeesCmd)
+	gasCmd.AddCommand(gasSimulateCmd)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	csvPath, generateImageDir, generateOutputDir, generateImageBaseURL string
+	generateStartTokenID                                               int
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate [command]",
+	Short: "Generate the files of a collection from the artist inputs",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var generateMetaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "generate metadata json from a CSV, columns named 'Trait:number' (or boost_number, boost_percentage, date) are numeric traits",
+	Run: func(cmd *cobra.Command, args []string) {
+		generator, err := getMetaGenerator()
+		if err != nil {
+			panic(err)
+		}
+
+		count, err := generator.Generate()
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("generated metadata count: [%d]\n", count)
+	},
+}
+
+func init() {
+	generateMetaCmd.Flags().StringVarP(&csvPath, "csv", "c", "", "CSV file with a header row and a row per token")
+	generateMetaCmd.Flags().StringVar(&generateImageDir, "imageDir", "", "the folder of the images named in the image column, internal/image by default")
+	generateMetaCmd.Flags().StringVarP(&generateOutputDir, "outputDir", "o", "", "the output folder of the metadata, internal/meta by default")
+	generateMetaCmd.Flags().StringVarP(&generateImageBaseURL, "imageBaseUrl", "g", "", "base url prefixing the image file names")
+	generateMetaCmd.Flags().IntVarP(&generateStartTokenID, "startTokenId", "s", 1, "token id of the first row when there is no id column")
+
+	generateCmd.AddCommand(generateMetaCmd)
+}
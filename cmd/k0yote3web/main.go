@@ -8,10 +8,20 @@ import (
 )
 
 var (
-	privateKey         string
-	chainRpcUrl        string
-	apiKey             string
-	thirdpartyProvider string
+	privateKey           string
+	keystorePath         string
+	mnemonic             string
+	derivationPath       string
+	chainRpcUrl          string
+	failoverRpcUrls      []string
+	apiKey               string
+	thirdpartyProvider   string
+	quickNodeEndpoint    string
+	rpcHeaders           []string
+	gasSpeed             string
+	maxFeePerGas         string
+	maxPriorityFeePerGas string
+	dryRun               bool
 
 	rootCmd = &cobra.Command{
 		Use:   "k0yote3web",
@@ -28,15 +38,39 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVarP(&privateKey, "privateKey", "k", "", "private key used to sign transactions")
-	rootCmd.PersistentFlags().StringVarP(&chainRpcUrl, "chainRpcUrl", "u", "mumbai", "chain url where all rpc requests will be sent")
-	rootCmd.PersistentFlags().StringVarP(&thirdpartyProvider, "thirdpartyProvider", "n", "alchemy", "third party provider")
+	rootCmd.PersistentFlags().StringVar(&keystorePath, "keystore", "", "encrypted keystore file used to sign transactions (passphrase from KEYSTORE_PASSPHRASE or prompt)")
+	rootCmd.PersistentFlags().StringVar(&mnemonic, "mnemonic", "", "BIP-39 mnemonic used to sign transactions (prefer the MNEMONIC env)")
+	rootCmd.PersistentFlags().StringVar(&derivationPath, "derivationPath", "", "mnemonic derivation path (default m/44'/60'/0'/0/0)")
+	rootCmd.PersistentFlags().StringVarP(&chainRpcUrl, "chainRpcUrl", "u", "polygon-amoy", "chain name (see the chains command) or url where all rpc requests will be sent")
+	rootCmd.PersistentFlags().StringSliceVar(&failoverRpcUrls, "failoverRpcUrls", nil, "more rpc urls of the same chain, requests fail over between them and chainRpcUrl")
+	rootCmd.PersistentFlags().StringVarP(&thirdpartyProvider, "thirdpartyProvider", "n", "alchemy", "third party provider (infura, alchemy, quicknode, ankr, public, local), ignored when chainRpcUrl is an url")
+	rootCmd.PersistentFlags().StringVar(&quickNodeEndpoint, "quickNodeEndpoint", "", "name of the QuickNode endpoint, the first label of its host")
+	rootCmd.PersistentFlags().StringArrayVar(&rpcHeaders, "rpcHeader", nil, "HTTP header sent with rpc requests as 'Name: value', e.g. 'Authorization: Bearer <jwt>'")
 	rootCmd.PersistentFlags().StringVarP(&apiKey, "apiKey", "a", "", "node provider api key")
+	rootCmd.PersistentFlags().StringVar(&gasSpeed, "gasSpeed", "", "price transactions from the fee history at this speed (slow, standard, fast) instead of the node suggestion")
+	rootCmd.PersistentFlags().StringVar(&maxFeePerGas, "maxFeePerGas", "", "cap of the fee per gas in gwei")
+	rootCmd.PersistentFlags().StringVar(&maxPriorityFeePerGas, "maxPriorityFeePerGas", "", "cap of the priority fee per gas in gwei")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dryRun", false, "simulate transactions and print calldata, gas and cost instead of sending them")
 	_ = viper.BindPFlag("privateKey", rootCmd.PersistentFlags().Lookup("privateKey"))
+	_ = viper.BindPFlag("keystore", rootCmd.PersistentFlags().Lookup("keystore"))
+	_ = viper.BindPFlag("mnemonic", rootCmd.PersistentFlags().Lookup("mnemonic"))
+	_ = viper.BindPFlag("derivationPath", rootCmd.PersistentFlags().Lookup("derivationPath"))
 	_ = viper.BindPFlag("chainRpcUrl", rootCmd.PersistentFlags().Lookup("chainRpcUrl"))
-	viper.SetDefault("chainRpcUrl", "polygon-mumbai")
+	_ = viper.BindPFlag("failoverRpcUrls", rootCmd.PersistentFlags().Lookup("failoverRpcUrls"))
+	viper.SetDefault("chainRpcUrl", "polygon-amoy")
 
 	rootCmd.AddCommand(downloadCmd)
 	rootCmd.AddCommand(rewriteCmd)
+	rootCmd.AddCommand(txCmd)
+	rootCmd.AddCommand(chainsCmd)
+	rootCmd.AddCommand(gasCmd)
+	rootCmd.AddCommand(walletCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(revealCmd)
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(exportCmd)
 }
 
 func initConfig() {
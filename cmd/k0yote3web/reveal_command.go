@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+var (
+	revealInputDir, revealOutputDir, revealImageDir, placeholderDir string
+	placeholderName, placeholderDescription, placeholderImage       string
+	revealSeed                                                      string
+	revealBlock                                                     int64
+	revealStartTokenID, revealEndTokenID                            int
+)
+
+var revealCmd = &cobra.Command{
+	Use:   "reveal [command]",
+	Short: "Prepare the placeholder and the revealed metadata of a drop",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var revealPlaceholderCmd = &cobra.Command{
+	Use:   "placeholder",
+	Short: "generate the pre-reveal metadata of a token range",
+	Run: func(cmd *cobra.Command, args []string) {
+		reveal, err := getReveal()
+		if err != nil {
+			panic(err)
+		}
+
+		count, err := reveal.GeneratePlaceholders(k0yote3web.MetaData{
+			Name:        placeholderName,
+			Description: placeholderDescription,
+			Image:       placeholderImage,
+			Attributes:  []k0yote3web.Attribute{},
+		}, revealStartTokenID, revealEndTokenID)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("generated placeholder metadata count: [%d]\n", count)
+	},
+}
+
+var revealProvenanceCmd = &cobra.Command{
+	Use:   "provenance",
+	Short: "hash the images of the final metadata to publish before the reveal",
+	Run: func(cmd *cobra.Command, args []string) {
+		reveal, err := getReveal()
+		if err != nil {
+			panic(err)
+		}
+
+		provenance, err := reveal.Provenance()
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("hashed images: [%d]\n", len(provenance.Images))
+		log.Printf("provenance hash: [%s]\n", provenance.ProvenanceHash)
+	},
+}
+
+var revealShuffleCmd = &cobra.Command{
+	Use:   "shuffle",
+	Short: "shuffle the final metadata from a block hash and record the assignment",
+	Run: func(cmd *cobra.Command, args []string) {
+		reveal, err := getReveal()
+		if err != nil {
+			panic(err)
+		}
+
+		seed := common.HexToHash(revealSeed)
+		if revealSeed == "" {
+			var number *big.Int
+			if revealBlock > 0 {
+				number = big.NewInt(revealBlock)
+			}
+			if seed, err = reveal.SeedFromBlock(context.Background(), number); err != nil {
+				panic(err)
+			}
+		}
+
+		record, err := reveal.Reveal(seed, revealStartTokenID)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("revealed tokens: [%d] seed: [%s]\n", len(record.Tokens), record.Seed.Hex())
+	},
+}
+
+func init() {
+	revealCmd.PersistentFlags().IntVarP(&revealStartTokenID, "startTokenId", "s", 1, "first token id")
+
+	revealPlaceholderCmd.Flags().IntVarP(&revealEndTokenID, "endTokenId", "e", 0, "last token id")
+	revealPlaceholderCmd.Flags().StringVar(&placeholderName, "name", "", "name of the placeholders, {id} is replaced with the token id")
+	revealPlaceholderCmd.Flags().StringVar(&placeholderDescription, "description", "", "description of the placeholders")
+	revealPlaceholderCmd.Flags().StringVar(&placeholderImage, "image", "", "image url of the placeholders")
+	revealPlaceholderCmd.Flags().StringVarP(&placeholderDir, "outputDir", "o", "", "the output folder of the placeholders, internal/placeholder by default")
+
+	revealShuffleCmd.Flags().StringVar(&revealSeed, "seed", "", "seed of the shuffle, the hash of --block when empty")
+	revealShuffleCmd.Flags().Int64Var(&revealBlock, "block", 0, "block whose hash seeds the shuffle, the latest block by default")
+	revealProvenanceCmd.Flags().StringVarP(&revealInputDir, "inputDir", "i", "", "the folder of the final metadata, internal/upload by default")
+	revealProvenanceCmd.Flags().StringVar(&revealImageDir, "imageDir", "", "the folder of the images hashed into the provenance, internal/image by default")
+
+	revealShuffleCmd.Flags().StringVarP(&revealInputDir, "inputDir", "i", "", "the folder of the final metadata, internal/upload by default")
+	revealShuffleCmd.Flags().StringVarP(&revealOutputDir, "outputDir", "o", "", "the output folder of the revealed metadata, internal/reveal by default")
+
+	revealCmd.AddCommand(revealPlaceholderCmd)
+	revealCmd.AddCommand(revealProvenanceCmd)
+	revealCmd.AddCommand(revealShuffleCmd)
+}
@@ -4,10 +4,12 @@ import (
 	"log"
 
 	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
 )
 
 var (
-	ipfsImageBaseURL, inputDir, outputDir string
+	ipfsImageBaseURL, inputDir, outputDir, rulesFile string
+	preview, externalizeDataURIs                     bool
 )
 
 var rewriteCmd = &cobra.Command{
@@ -21,13 +23,46 @@ var rewriteCmd = &cobra.Command{
 
 var rewriteMetaCmd = &cobra.Command{
 	Use:   "replace-meta",
-	Short: "rewrite image url with ipfs in metadata json",
+	Short: "rewrite image url with ipfs and apply the rules to metadata json",
 	Run: func(cmd *cobra.Command, args []string) {
 		rewriter, err := getRewrite()
 		if err != nil {
 			panic(err)
 		}
 
+		if rulesFile != "" {
+			rules, err := k0yote3web.LoadMetaRules(rulesFile)
+			if err != nil {
+				panic(err)
+			}
+			rewriter.SetRules(rules)
+		}
+
+		rewriter.SetExternalizeDataURIs(externalizeDataURIs)
+
+		if preview {
+			previews, err := rewriter.Preview()
+			if err != nil {
+				panic(err)
+			}
+
+			changed := 0
+			for _, p := range previews {
+				changes := p.Changes()
+				if len(changes) == 0 {
+					continue
+				}
+				changed++
+				log.Printf("file: [%s]\n", p.File)
+				for _, change := range changes {
+					log.Printf("  %s\n", change)
+				}
+			}
+
+			log.Printf("files to rewrite: [%d] changed: [%d]\n", len(previews), changed)
+			return
+		}
+
 		if err := rewriter.Rewrite(); err != nil {
 			panic(err)
 		}
@@ -41,5 +76,9 @@ func init() {
 	rewriteCmd.PersistentFlags().StringVarP(&inputDir, "inputDir", "i", "", "the folder of metadata files")
 	rewriteCmd.PersistentFlags().StringVarP(&outputDir, "outputDir", "o", "", "the output folder of replaced image urls with ipfs")
 
+	rewriteMetaCmd.Flags().StringVarP(&rulesFile, "rules", "r", "", "YAML or JSON file of the rules applied to every metadata file")
+	rewriteMetaCmd.Flags().BoolVar(&externalizeDataURIs, "externalizeDataUris", false, "replace inline data uri images with the uploaded files too")
+	rewriteMetaCmd.Flags().BoolVar(&preview, "preview", false, "print the changes of every file instead of writing them")
+
 	rewriteCmd.AddCommand(rewriteMetaCmd)
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+var (
+	txHash        string
+	replaceAfter  time.Duration
+	bumpPercent   int64
+	confirmations uint64
+)
+
+var txCmd = &cobra.Command{
+	Use:   "tx [command]",
+	Short: "Speed up or cancel a pending transaction sent from the signer",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var txSpeedUpCmd = &cobra.Command{
+	Use:   "speed-up",
+	Short: "re-submit a pending transaction with bumped fees and wait until it is mined",
+	Run: func(cmd *cobra.Command, args []string) {
+		replacePendingTx(func(monitor *k0yote3web.TxMonitor, tx *types.Transaction) (*types.Transaction, error) {
+			return monitor.SpeedUp(txOpts(), tx)
+		})
+	},
+}
+
+var txCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "replace a pending transaction with a 0-value self-transfer and wait until it is mined",
+	Run: func(cmd *cobra.Command, args []string) {
+		replacePendingTx(func(monitor *k0yote3web.TxMonitor, tx *types.Transaction) (*types.Transaction, error) {
+			return monitor.Cancel(txOpts(), tx)
+		})
+	},
+}
+
+func replacePendingTx(replace func(*k0yote3web.TxMonitor, *types.Transaction) (*types.Transaction, error)) {
+	monitor, err := getTxMonitor()
+	if err != nil {
+		panic(err)
+	}
+
+	tx, isPending, err := k0yote3webSDK.GetProvider().TransactionByHash(context.Background(), common.HexToHash(txHash))
+	if err != nil {
+		panic(err)
+	}
+	if !isPending {
+		panic(fmt.Errorf("transaction %s is not pending", txHash))
+	}
+
+	replacement, err := replace(monitor, tx)
+	if err != nil {
+		panic(err)
+	}
+	if k0yote3webSDK.IsDryRun() {
+		return
+	}
+	log.Printf("submitted replacement tx: [%s] nonce: [%d]\n", replacement.Hash().Hex(), replacement.Nonce())
+
+	result, err := monitor.Wait(txOpts(), tx, replacement)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("transaction mined hash: [%s] block: [%d] submitted: [%d]\n", result.Hash.Hex(), result.Receipt.BlockNumber, len(result.Submitted))
+	if chain, err := k0yote3webSDK.GetChain(context.Background()); err == nil {
+		log.Printf("explorer: [%s]\n", chain.TxURL(result.Hash))
+	}
+}
+
+func init() {
+	txCmd.PersistentFlags().StringVarP(&txHash, "txHash", "x", "", "hash of the pending transaction")
+	txCmd.PersistentFlags().DurationVar(&replaceAfter, "replaceAfter", 3*time.Minute, "wait before re-submitting with bumped fees")
+	txCmd.PersistentFlags().Int64Var(&bumpPercent, "bumpPercent", 10, "fee increase in percent for each replacement (at least 10)")
+	txCmd.PersistentFlags().Uint64Var(&confirmations, "confirmations", 1, "blocks, counting its own, to wait for once the transaction is mined")
+
+	txCmd.AddCommand(txSpeedUpCmd)
+	txCmd.AddCommand(txCancelCmd)
+}
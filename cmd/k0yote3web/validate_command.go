@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateInputDir string
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [command]",
+	Short: "Check files against the ERC-721/ERC-1155 standards and marketplace conventions",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var validateMetaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "validate the metadata json files of a folder and report the errors and warnings per file",
+	Run: func(cmd *cobra.Command, args []string) {
+		validator, err := getMetaValidator()
+		if err != nil {
+			panic(err)
+		}
+
+		reports, err := validator.Validate()
+		if err != nil {
+			panic(err)
+		}
+
+		invalid := 0
+		for _, report := range reports {
+			if !report.Valid() {
+				invalid++
+			}
+			for _, issue := range report.Issues {
+				log.Printf("file: [%s] %s\n", report.File, issue)
+			}
+		}
+
+		log.Printf("validated metadata files: [%d] invalid: [%d]\n", len(reports), invalid)
+		if invalid > 0 {
+			log.Fatalln("metadata validation failed")
+		}
+	},
+}
+
+func init() {
+	validateCmd.PersistentFlags().StringVarP(&validateInputDir, "inputDir", "i", "", "the folder of metadata files, internal/meta by default (internal/upload for rewritten files)")
+
+	validateCmd.AddCommand(validateMetaCmd)
+}
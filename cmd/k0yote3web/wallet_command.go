@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+var (
+	recipient string
+	amount    string
+)
+
+var walletCmd = &cobra.Command{
+	Use:   "wallet [command]",
+	Short: "Send the native currency of the chain from the signer",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("Please input a command to run")
+	},
+}
+
+var walletTransferCmd = &cobra.Command{
+	Use:   "transfer",
+	Short: "send an amount of the native currency, in ether units, to an address",
+	Run: func(cmd *cobra.Command, args []string) {
+		value := k0yote3web.ToWeiR(amount)
+		if value.Sign() <= 0 {
+			panic(fmt.Errorf("invalid amount: %s", amount))
+		}
+
+		sendFromWallet(func(wallet *k0yote3web.Wallet, to common.Address) (*types.Transaction, error) {
+			return wallet.Transfer(txOpts(), to, value)
+		}, false)
+	},
+}
+
+var walletSweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "send the whole balance, less the fees, to an address",
+	Run: func(cmd *cobra.Command, args []string) {
+		sendFromWallet(func(wallet *k0yote3web.Wallet, to common.Address) (*types.Transaction, error) {
+			return wallet.Sweep(txOpts(), to)
+		}, true)
+	},
+}
+
+// sendFromWallet sends the transaction of send and waits for it, replacing it when it is stuck.
+// The replacements of a sweep send the balance less their bumped fees.
+func sendFromWallet(send func(*k0yote3web.Wallet, common.Address) (*types.Transaction, error), sweep bool) {
+	if !common.IsHexAddress(recipient) {
+		panic(fmt.Errorf("invalid recipient address: %s", recipient))
+	}
+
+	wallet, err := getWallet()
+	if err != nil {
+		panic(err)
+	}
+
+	tx, err := send(wallet, common.HexToAddress(recipient))
+	if err != nil {
+		panic(err)
+	}
+	if k0yote3webSDK.IsDryRun() {
+		return
+	}
+	log.Printf("submitted tx: [%s] value: [%s]\n", tx.Hash().Hex(), k0yote3web.ToEther(tx.Value()).Text('f', 18))
+
+	monitor, err := getTxMonitor()
+	if err != nil {
+		panic(err)
+	}
+	wait := monitor.Wait
+	if sweep {
+		wait = monitor.WaitSweep
+	}
+	result, err := wait(txOpts(), tx)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("transaction mined hash: [%s] block: [%d]\n", result.Hash.Hex(), result.Receipt.BlockNumber)
+	if chain, err := k0yote3webSDK.GetChain(context.Background()); err == nil {
+		log.Printf("explorer: [%s]\n", chain.TxURL(result.Hash))
+	}
+}
+
+func init() {
+	walletCmd.PersistentFlags().StringVarP(&recipient, "to", "r", "", "address receiving the funds")
+	walletTransferCmd.Flags().StringVar(&amount, "amount", "", "amount in ether units, e.g. 0.5")
+
+	walletCmd.AddCommand(walletTransferCmd)
+	walletCmd.AddCommand(walletSweepCmd)
+}
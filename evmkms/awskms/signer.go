@@ -15,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/pkg/errors"
 	common2 "github.com/thirdtool-dev/go-sdk/evmkms/common"
 )
@@ -107,6 +108,31 @@ func (c AmazonKMSClient) SignHash(digest common.Hash) ([]byte, error) {
 	return c.parseKMSSignature(digest, result.Signature)
 }
 
+// SignMessage signs msg following EIP-191 (personal_sign).
+func (c AmazonKMSClient) SignMessage(msg []byte) ([]byte, error) {
+	sig, err := c.SignHash(common2.TextHash(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	return common2.ToMessageSignature(sig)
+}
+
+// SignTypedData signs typedData following EIP-712.
+func (c AmazonKMSClient) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	digest, err := common2.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := c.SignHash(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return common2.ToMessageSignature(sig)
+}
+
 func (c AmazonKMSClient) GetDefaultEVMTransactor() *bind.TransactOpts {
 	return &bind.TransactOpts{
 		Context: c.ctx,
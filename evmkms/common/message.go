@@ -0,0 +1,60 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// TextHash returns the EIP-191 (personal_sign) digest of msg.
+func TextHash(msg []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(msg))
+}
+
+// TypedDataHash returns the EIP-712 digest of typedData.
+func TypedDataHash(typedData apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.BytesToHash(hash), nil
+}
+
+// ToMessageSignature converts a signature with a recovery id of 0 or 1
+// into the 27 or 28 form expected by ecrecover and wallets.
+func ToMessageSignature(sig []byte) ([]byte, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+
+	ret := bytes.Clone(sig)
+	if ret[crypto.RecoveryIDOffset] < 27 {
+		ret[crypto.RecoveryIDOffset] += 27
+	}
+
+	return ret, nil
+}
+
+// RecoverMessageSigner returns the address that produced a 27/28 signature over digest.
+func RecoverMessageSigner(digest common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+
+	rsv := bytes.Clone(sig)
+	if rsv[crypto.RecoveryIDOffset] >= 27 {
+		rsv[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(digest[:], rsv)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
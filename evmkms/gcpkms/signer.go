@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	common2 "github.com/thirdtool-dev/go-sdk/evmkms/common"
 	"google.golang.org/api/iterator"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -97,6 +98,31 @@ func (c GoogleKMSClient) SignHash(digest common.Hash) ([]byte, error) {
 	return c.parseKMSSignature(digest, result.Signature)
 }
 
+// SignMessage signs msg following EIP-191 (personal_sign).
+func (c GoogleKMSClient) SignMessage(msg []byte) ([]byte, error) {
+	sig, err := c.SignHash(common2.TextHash(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	return common2.ToMessageSignature(sig)
+}
+
+// SignTypedData signs typedData following EIP-712.
+func (c GoogleKMSClient) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	digest, err := common2.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := c.SignHash(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return common2.ToMessageSignature(sig)
+}
+
 func (c GoogleKMSClient) GetDefaultEVMTransactor() *bind.TransactOpts {
 	return &bind.TransactOpts{
 		Context: c.ctx,
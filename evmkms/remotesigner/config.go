@@ -0,0 +1,47 @@
+package remotesigner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Config struct {
+	Endpoint string            `json:"Endpoint"`
+	Address  string            `json:"Address"`
+	ChainID  uint64            `json:"ChainID"`
+	Headers  map[string]string `json:"Headers,omitempty"`
+}
+
+func (cfg Config) IsValid() (bool, error) {
+	if cfg.Endpoint == "" {
+		return false, fmt.Errorf("empty Endpoint")
+	}
+
+	if !common.IsHexAddress(cfg.Address) {
+		return false, fmt.Errorf("invalid Address")
+	}
+
+	return true, nil
+}
+
+func LoadConfigFromFile(filePath string) (*Config, error) {
+	f, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	err = json.Unmarshal(f, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = cfg.IsValid(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
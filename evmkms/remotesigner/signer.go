@@ -0,0 +1,243 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	common2 "github.com/thirdtool-dev/go-sdk/evmkms/common"
+)
+
+var errUnsupported = errors.New("not supported by remote signers")
+
+// RemoteSignerClient signs transactions through an eth_signTransaction compatible
+// JSON-RPC endpoint such as Clef or Web3Signer.
+type RemoteSignerClient struct {
+	rpcClient *rpc.Client
+	ctx       context.Context
+	cfg       Config
+	address   common.Address
+	signer    types.Signer
+}
+
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big     `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+func NewRemoteSignerClient(ctx context.Context, cfg Config, txSigner ...types.Signer) (*RemoteSignerClient, error) {
+	if _, err := cfg.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid config")
+	}
+
+	signer := types.NewLondonSigner(new(big.Int).SetUint64(cfg.ChainID))
+	if len(txSigner) > 0 {
+		signer = txSigner[0]
+	}
+
+	headers := http.Header{}
+	for k, v := range cfg.Headers {
+		headers.Set(k, v)
+	}
+
+	client, err := rpc.DialOptions(ctx, cfg.Endpoint, rpc.WithHeaders(headers))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteSignerClient{
+		rpcClient: client,
+		ctx:       ctx,
+		cfg:       cfg,
+		address:   common.HexToAddress(cfg.Address),
+		signer:    signer,
+	}, nil
+}
+
+func (c RemoteSignerClient) GetAddress() common.Address {
+	return c.address
+}
+
+func (c RemoteSignerClient) GetPublicKey() (*ecdsa.PublicKey, error) {
+	return nil, fmt.Errorf("public key export: %w", errUnsupported)
+}
+
+func (c RemoteSignerClient) SignHash(digest common.Hash) ([]byte, error) {
+	return nil, fmt.Errorf("raw digest signing: %w", errUnsupported)
+}
+
+// SignMessage signs msg following EIP-191 through eth_sign.
+func (c RemoteSignerClient) SignMessage(msg []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := c.rpcClient.CallContext(c.ctx, &sig, "eth_sign", c.GetAddress(), hexutil.Bytes(msg)); err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+
+	return c.verifyMessageSignature(common2.TextHash(msg), sig)
+}
+
+// SignTypedData signs typedData following EIP-712 through eth_signTypedData_v4.
+func (c RemoteSignerClient) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	digest, err := common2.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig hexutil.Bytes
+	if err := c.rpcClient.CallContext(c.ctx, &sig, "eth_signTypedData_v4", c.GetAddress(), typedData); err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %v", err)
+	}
+
+	return c.verifyMessageSignature(digest, sig)
+}
+
+func (c RemoteSignerClient) GetDefaultEVMTransactor() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		Context: c.ctx,
+		From:    c.GetAddress(),
+		Signer:  c.GetEVMSignerFn(),
+	}
+}
+
+func (c RemoteSignerClient) GetEVMSignerFn() bind.SignerFn {
+	return func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if addr != c.GetAddress() {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		ret, err := c.signTransaction(tx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot sign transaction: %v", err)
+		}
+
+		if c.signer.Hash(ret) != c.signer.Hash(tx) {
+			return nil, fmt.Errorf("remote signer returned a different transaction")
+		}
+
+		if _, err = c.HasSignedTx(ret); err != nil {
+			return nil, err
+		}
+
+		return ret, nil
+	}
+}
+
+func (c RemoteSignerClient) HasSignedTx(tx *types.Transaction) (bool, error) {
+	from, err := types.Sender(c.signer, tx)
+	if err != nil {
+		return false, fmt.Errorf("cannot get sender of the tx: %v", err)
+	}
+
+	if from != c.GetAddress() {
+		return false, fmt.Errorf("expected signer: %v, got %v", c.GetAddress(), from)
+	}
+
+	return true, nil
+}
+
+func (c *RemoteSignerClient) WithSigner(signer types.Signer) {
+	c.signer = signer
+}
+
+func (c *RemoteSignerClient) WithChainID(chainID *big.Int) {
+	if c.cfg.ChainID != chainID.Uint64() {
+		c.cfg.ChainID = chainID.Uint64()
+		c.signer = types.NewLondonSigner(chainID)
+	}
+}
+
+func (c RemoteSignerClient) Close() {
+	c.rpcClient.Close()
+}
+
+func (c RemoteSignerClient) verifyMessageSignature(digest common.Hash, sig []byte) ([]byte, error) {
+	ret, err := common2.ToMessageSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := common2.RecoverMessageSigner(digest, ret)
+	if err != nil {
+		return nil, fmt.Errorf("cannot recover signer: %v", err)
+	}
+
+	if from != c.GetAddress() {
+		return nil, fmt.Errorf("expected signer: %v, got %v", c.GetAddress(), from)
+	}
+
+	return ret, nil
+}
+
+func (c RemoteSignerClient) signTransaction(tx *types.Transaction) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:    c.GetAddress(),
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(c.signer.ChainID()),
+	}
+	if tx.Type() == types.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	}
+
+	var result json.RawMessage
+	if err := c.rpcClient.CallContext(c.ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, err
+	}
+
+	raw, err := parseSignTxResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := new(types.Transaction)
+	if err := ret.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("cannot decode signed transaction: %v", err)
+	}
+
+	return ret, nil
+}
+
+// parseSignTxResult accepts both the raw transaction returned by Web3Signer
+// and the {"raw": ..., "tx": ...} object returned by Clef.
+func parseSignTxResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var signed struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &signed); err != nil {
+		return nil, fmt.Errorf("unexpected eth_signTransaction result: %s", result)
+	}
+	if len(signed.Raw) == 0 {
+		return nil, fmt.Errorf("empty eth_signTransaction result")
+	}
+
+	return signed.Raw, nil
+}
@@ -0,0 +1,216 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	common2 "github.com/thirdtool-dev/go-sdk/evmkms/common"
+)
+
+type stubSigner struct {
+	key  *ecdsa.PrivateKey
+	clef bool
+}
+
+func (s *stubSigner) SignTransaction(args signTxArgs) (any, error) {
+	var tx *types.Transaction
+	if args.MaxFeePerGas != nil {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		})
+	} else {
+		tx = types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    args.Value.ToInt(),
+			Data:     args.Data,
+		})
+	}
+
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.clef {
+		return map[string]any{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+	}
+	return hexutil.Bytes(raw), nil
+}
+
+func (s *stubSigner) Sign(address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	return s.signDigest(common2.TextHash(data))
+}
+
+func (s *stubSigner) SignTypedData_v4(address common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	digest, err := common2.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signDigest(digest)
+}
+
+func (s *stubSigner) signDigest(digest common.Hash) (hexutil.Bytes, error) {
+	sig, err := crypto.Sign(digest[:], s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return sig, nil
+}
+
+func newStubClient(t *testing.T, stub *stubSigner, address common.Address) *RemoteSignerClient {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", stub))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	client, err := NewRemoteSignerClient(context.Background(), Config{
+		Endpoint: httpServer.URL,
+		Address:  address.Hex(),
+		ChainID:  1337,
+	})
+	assert.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func testTransactions() []*types.Transaction {
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	return []*types.Transaction{
+		types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(1337),
+			Nonce:     3,
+			GasTipCap: big.NewInt(1_000_000_000),
+			GasFeeCap: big.NewInt(30_000_000_000),
+			Gas:       21_000,
+			To:        &to,
+			Value:     big.NewInt(1),
+		}),
+		types.NewTx(&types.LegacyTx{
+			Nonce:    4,
+			GasPrice: big.NewInt(20_000_000_000),
+			Gas:      50_000,
+			To:       &to,
+			Value:    big.NewInt(0),
+			Data:     []byte{0xde, 0xad, 0xbe, 0xef},
+		}),
+	}
+}
+
+func TestRemoteSignerSignTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	for _, clef := range []bool{false, true} {
+		client := newStubClient(t, &stubSigner{key: key, clef: clef}, address)
+		transactor := client.GetDefaultEVMTransactor()
+
+		for _, tx := range testTransactions() {
+			signed, err := transactor.Signer(address, tx)
+			assert.NoError(t, err)
+			assert.Equal(t, tx.Nonce(), signed.Nonce())
+
+			ok, err := client.HasSignedTx(signed)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}
+	}
+}
+
+func TestRemoteSignerRejectsWrongSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	client := newStubClient(t, &stubSigner{key: other}, address)
+
+	_, err = client.GetEVMSignerFn()(address, testTransactions()[0])
+	assert.Error(t, err)
+
+	_, err = client.GetEVMSignerFn()(crypto.PubkeyToAddress(other.PublicKey), testTransactions()[0])
+	assert.Error(t, err)
+}
+
+func TestRemoteSignerSignMessage(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	client := newStubClient(t, &stubSigner{key: key}, address)
+
+	msg := []byte("hello")
+	sig, err := client.SignMessage(msg)
+	assert.NoError(t, err)
+
+	from, err := common2.RecoverMessageSigner(common2.TextHash(msg), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, address, from)
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Voucher": {
+				{Name: "tokenId", Type: "uint256"},
+				{Name: "uri", Type: "string"},
+			},
+		},
+		PrimaryType: "Voucher",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "LazyMint",
+			ChainId: math.NewHexOrDecimal256(1337),
+		},
+		Message: apitypes.TypedDataMessage{
+			"tokenId": "1",
+			"uri":     "ipfs://bafy/1.json",
+		},
+	}
+	sig, err = client.SignTypedData(typedData)
+	assert.NoError(t, err)
+
+	digest, err := common2.TypedDataHash(typedData)
+	assert.NoError(t, err)
+	from, err = common2.RecoverMessageSigner(digest, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, address, from)
+
+	other, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	client = newStubClient(t, &stubSigner{key: other}, address)
+	_, err = client.SignMessage(msg)
+	assert.Error(t, err)
+}
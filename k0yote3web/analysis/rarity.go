@@ -0,0 +1,187 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+// NoneValue is the value counted for the tokens missing a trait type, absent traits make a token rare too
+const NoneValue = "None"
+
+type TraitStat struct {
+	TraitType string  `json:"trait_type"`
+	Value     string  `json:"value"`
+	Count     int     `json:"count"`
+	Frequency float64 `json:"frequency"`
+}
+
+type TokenRarity struct {
+	File string `json:"file"`
+	Name string `json:"name"`
+	// Rank is 1 for the rarest token, tokens of equal score share a rank
+	Rank int `json:"rank"`
+	// StatisticalRarity is the product of the frequencies of the token traits, the lower the rarer
+	StatisticalRarity float64 `json:"statistical_rarity"`
+	// InformationContent is the sum of -log2 of the frequencies of the token traits, the higher the rarer
+	InformationContent float64 `json:"information_content"`
+}
+
+type RarityReport struct {
+	Tokens int `json:"tokens"`
+	// Traits are ordered by trait type then by count, most common first
+	Traits []TraitStat `json:"traits"`
+	// Rarity is ordered by rank
+	Rarity []TokenRarity `json:"rarity"`
+}
+
+// Rarity computes the trait distribution of metas and ranks the tokens by information content.
+// A trait type repeated on a token is counted apart from the second one on, e.g. Accessory#2.
+func Rarity(metas []*k0yote3web.MetaDataFile) *RarityReport {
+	counts := make(map[string]map[string]int)
+	tokenTraits := make([]map[string]string, len(metas))
+	for i, meta := range metas {
+		tokenTraits[i] = make(map[string]string, len(meta.Attributes))
+		seen := make(map[string]int, len(meta.Attributes))
+		for _, attribute := range meta.Attributes {
+			traitType := attribute.TraitType
+			seen[traitType]++
+			if seen[traitType] > 1 {
+				traitType = fmt.Sprintf("%s#%d", traitType, seen[traitType])
+			}
+
+			value := traitValue(attribute.Value)
+			tokenTraits[i][traitType] = value
+			if counts[traitType] == nil {
+				counts[traitType] = make(map[string]int)
+			}
+			counts[traitType][value]++
+		}
+	}
+
+	for i := range metas {
+		for traitType := range counts {
+			if _, ok := tokenTraits[i][traitType]; !ok {
+				tokenTraits[i][traitType] = NoneValue
+				counts[traitType][NoneValue]++
+			}
+		}
+	}
+
+	report := &RarityReport{Tokens: len(metas)}
+	frequency := func(traitType, value string) float64 {
+		return float64(counts[traitType][value]) / float64(len(metas))
+	}
+
+	for traitType, values := range counts {
+		for value, count := range values {
+			report.Traits = append(report.Traits, TraitStat{
+				TraitType: traitType,
+				Value:     value,
+				Count:     count,
+				Frequency: frequency(traitType, value),
+			})
+		}
+	}
+	sort.Slice(report.Traits, func(i, j int) bool {
+		a, b := report.Traits[i], report.Traits[j]
+		if a.TraitType != b.TraitType {
+			return a.TraitType < b.TraitType
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value < b.Value
+	})
+
+	for i, meta := range metas {
+		rarity := TokenRarity{File: meta.File, Name: meta.Name, StatisticalRarity: 1}
+		for traitType, value := range tokenTraits[i] {
+			f := frequency(traitType, value)
+			rarity.StatisticalRarity *= f
+			rarity.InformationContent -= math.Log2(f)
+		}
+		report.Rarity = append(report.Rarity, rarity)
+	}
+	sort.SliceStable(report.Rarity, func(i, j int) bool {
+		return report.Rarity[i].InformationContent > report.Rarity[j].InformationContent
+	})
+	for i := range report.Rarity {
+		report.Rarity[i].Rank = i + 1
+		if i > 0 && sameScore(report.Rarity[i].InformationContent, report.Rarity[i-1].InformationContent) {
+			report.Rarity[i].Rank = report.Rarity[i-1].Rank
+		}
+	}
+
+	return report
+}
+
+// WriteJSON writes the whole report.
+func (r *RarityReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteRarityCSV writes a row per token in rank order.
+func (r *RarityReport) WriteRarityCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"rank", "file", "name", "statistical_rarity", "information_content"}); err != nil {
+		return err
+	}
+	for _, rarity := range r.Rarity {
+		if err := writer.Write([]string{
+			strconv.Itoa(rarity.Rank),
+			rarity.File,
+			rarity.Name,
+			strconv.FormatFloat(rarity.StatisticalRarity, 'g', -1, 64),
+			strconv.FormatFloat(rarity.InformationContent, 'f', 6, 64),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTraitsCSV writes a row per trait type and value.
+func (r *RarityReport) WriteTraitsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"trait_type", "value", "count", "frequency"}); err != nil {
+		return err
+	}
+	for _, trait := range r.Traits {
+		if err := writer.Write([]string{
+			trait.TraitType,
+			trait.Value,
+			strconv.Itoa(trait.Count),
+			strconv.FormatFloat(trait.Frequency, 'f', 6, 64),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func traitValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return NoneValue
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func sameScore(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thirdtool-dev/go-sdk/k0yote3web"
+)
+
+func meta(file string, attributes ...k0yote3web.Attribute) *k0yote3web.MetaDataFile {
+	return &k0yote3web.MetaDataFile{
+		File:     file,
+		MetaData: k0yote3web.MetaData{Name: "Foo #" + file, Attributes: attributes},
+	}
+}
+
+func TestRarity(t *testing.T) {
+	metas := []*k0yote3web.MetaDataFile{
+		meta("1", k0yote3web.Attribute{TraitType: "Eyes", Value: "Blue"}, k0yote3web.Attribute{TraitType: "Hat", Value: "Crown"}),
+		meta("2", k0yote3web.Attribute{TraitType: "Eyes", Value: "Blue"}),
+		meta("3", k0yote3web.Attribute{TraitType: "Eyes", Value: "Blue"}),
+		meta("4", k0yote3web.Attribute{TraitType: "Eyes", Value: "Red"}),
+	}
+
+	report := Rarity(metas)
+	assert.Equal(t, 4, report.Tokens)
+	assert.Equal(t, []TraitStat{
+		{TraitType: "Eyes", Value: "Blue", Count: 3, Frequency: 0.75},
+		{TraitType: "Eyes", Value: "Red", Count: 1, Frequency: 0.25},
+		{TraitType: "Hat", Value: NoneValue, Count: 3, Frequency: 0.75},
+		{TraitType: "Hat", Value: "Crown", Count: 1, Frequency: 0.25},
+	}, report.Traits)
+
+	// 1 and 4 both have a trait of frequency 0.25 and one of 0.75
+	assert.Equal(t, 1, report.Rarity[0].Rank)
+	assert.Equal(t, 1, report.Rarity[1].Rank)
+	assert.Equal(t, []string{"1", "4"}, []string{report.Rarity[0].File, report.Rarity[1].File})
+	assert.InDelta(t, 0.1875, report.Rarity[0].StatisticalRarity, 1e-9)
+	assert.InDelta(t, 2.415037, report.Rarity[0].InformationContent, 1e-6)
+	assert.Equal(t, 3, report.Rarity[2].Rank)
+	assert.InDelta(t, 0.5625, report.Rarity[3].StatisticalRarity, 1e-9)
+
+	var b bytes.Buffer
+	assert.NoError(t, report.WriteRarityCSV(&b))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "1,1,Foo #1,0.1875,2.415037", lines[1])
+}
+
+func TestRarityRepeatedTraitType(t *testing.T) {
+	metas := []*k0yote3web.MetaDataFile{
+		meta("1", k0yote3web.Attribute{TraitType: "Accessory", Value: "Hat"}, k0yote3web.Attribute{TraitType: "Accessory", Value: "Scarf"}),
+		meta("2", k0yote3web.Attribute{TraitType: "Accessory", Value: "Hat"}),
+	}
+
+	report := Rarity(metas)
+	// every token is counted once per trait type, the repeat is a trait type of its own
+	assert.Equal(t, []TraitStat{
+		{TraitType: "Accessory", Value: "Hat", Count: 2, Frequency: 1},
+		{TraitType: "Accessory#2", Value: NoneValue, Count: 1, Frequency: 0.5},
+		{TraitType: "Accessory#2", Value: "Scarf", Count: 1, Frequency: 0.5},
+	}, report.Traits)
+
+	assert.Equal(t, "1", report.Rarity[0].File)
+	assert.InDelta(t, 0.5, report.Rarity[0].StatisticalRarity, 1e-9)
+	assert.InDelta(t, 1, report.Rarity[0].InformationContent, 1e-9)
+	// ties with token 1, both have one trait of frequency 1 and one of 0.5
+	assert.Equal(t, 1, report.Rarity[1].Rank)
+}
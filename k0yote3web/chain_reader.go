@@ -0,0 +1,224 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	defaultReaderBatchSize  = 100
+	defaultReaderMaxRetries = 3
+)
+
+type ChainReaderOptions struct {
+	// BatchSize is the number of requests sent in one JSON-RPC batch
+	BatchSize int
+	// MaxRetries is how many times requests that failed for transient reasons are retried
+	MaxRetries int
+}
+
+// ChainReader reads many values from the chain through JSON-RPC batch requests.
+type ChainReader struct {
+	handler    *ProviderHandler
+	erc721     abi.ABI
+	batchSize  int
+	maxRetries int
+}
+
+type callArg struct {
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"input"`
+}
+
+func newChainReader(handler *ProviderHandler, opts *ChainReaderOptions) (*ChainReader, error) {
+	tokenABI, err := abi.JSON(strings.NewReader(erc721ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ChainReader{
+		handler:    handler,
+		erc721:     tokenABI,
+		batchSize:  defaultReaderBatchSize,
+		maxRetries: defaultReaderMaxRetries,
+	}
+	if opts != nil {
+		if opts.BatchSize > 0 {
+			r.batchSize = opts.BatchSize
+		}
+		if opts.MaxRetries > 0 {
+			r.maxRetries = opts.MaxRetries
+		}
+	}
+
+	return r, nil
+}
+
+// TokenURIs reads tokenURI of tokenIDs on an ERC-721 contract, the decoded value is a string.
+func (r *ChainReader) TokenURIs(ctx context.Context, contract common.Address, tokenIDs []*big.Int) ([]CallResult, error) {
+	args := make([]any, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		args = append(args, tokenID)
+	}
+
+	return r.callERC721(ctx, contract, "tokenURI", args)
+}
+
+// OwnersOf reads ownerOf of tokenIDs on an ERC-721 contract, the decoded value is a common.Address.
+func (r *ChainReader) OwnersOf(ctx context.Context, contract common.Address, tokenIDs []*big.Int) ([]CallResult, error) {
+	args := make([]any, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		args = append(args, tokenID)
+	}
+
+	return r.callERC721(ctx, contract, "ownerOf", args)
+}
+
+// BalancesOf reads balanceOf of owners on an ERC-721 contract, the decoded value is a *big.Int.
+func (r *ChainReader) BalancesOf(ctx context.Context, contract common.Address, owners []common.Address) ([]CallResult, error) {
+	args := make([]any, 0, len(owners))
+	for _, owner := range owners {
+		args = append(args, owner)
+	}
+
+	return r.callERC721(ctx, contract, "balanceOf", args)
+}
+
+// NativeBalances reads the native currency balance of accounts, the decoded value is a *big.Int.
+func (r *ChainReader) NativeBalances(ctx context.Context, accounts []common.Address) ([]CallResult, error) {
+	elems := make([]rpc.BatchElem, 0, len(accounts))
+	for _, account := range accounts {
+		elems = append(elems, rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []any{account, "latest"},
+			Result: new(hexutil.Big),
+		})
+	}
+
+	if err := r.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	results := make([]CallResult, 0, len(elems))
+	for _, elem := range elems {
+		if elem.Error != nil {
+			results = append(results, CallResult{Err: elem.Error})
+			continue
+		}
+		results = append(results, CallResult{Success: true, Value: elem.Result.(*hexutil.Big).ToInt()})
+	}
+
+	return results, nil
+}
+
+func (r *ChainReader) callERC721(ctx context.Context, contract common.Address, method string, args []any) ([]CallResult, error) {
+	elems := make([]rpc.BatchElem, 0, len(args))
+	for _, arg := range args {
+		input, err := r.erc721.Pack(method, arg)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []any{callArg{To: contract, Data: input}, "latest"},
+			Result: new(hexutil.Bytes),
+		})
+	}
+
+	if err := r.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	results := make([]CallResult, 0, len(elems))
+	for i, elem := range elems {
+		if elem.Error != nil {
+			results = append(results, CallResult{Err: elem.Error})
+			continue
+		}
+
+		data := *elem.Result.(*hexutil.Bytes)
+		values, err := r.erc721.Unpack(method, data)
+		if err != nil {
+			results = append(results, CallResult{ReturnData: data, Err: fmt.Errorf("cannot decode %s(%v): %v", method, args[i], err)})
+			continue
+		}
+		results = append(results, CallResult{Success: true, ReturnData: data, Value: values[0]})
+	}
+
+	return results, nil
+}
+
+// batchCall sends elems in batches of batchSize, retrying the requests that failed
+// for transient reasons. Requests that reverted keep their error in elem.Error.
+func (r *ChainReader) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	ctx = ensureContext(ctx)
+
+	for start := 0; start < len(elems); start += r.batchSize {
+		end := start + r.batchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+
+		pending := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			pending = append(pending, i)
+		}
+
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > r.maxRetries {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(attempt) * waitTime):
+				}
+			}
+
+			batch := make([]rpc.BatchElem, 0, len(pending))
+			for _, i := range pending {
+				elems[i].Error = nil
+				batch = append(batch, elems[i])
+			}
+
+			// The client is picked per attempt so a failover provider can move to another endpoint
+			if err := r.handler.GetProvider().Client().BatchCallContext(ctx, batch); err != nil {
+				if attempt >= r.maxRetries {
+					return err
+				}
+				continue
+			}
+
+			retry := make([]int, 0)
+			for j, i := range pending {
+				elems[i] = batch[j]
+				if elems[i].Error != nil && !isRevertError(elems[i].Error) {
+					retry = append(retry, i)
+				}
+			}
+			pending = retry
+		}
+	}
+
+	return nil
+}
+
+// isRevertError reports whether err is an execution revert, which retrying cannot fix.
+func isRevertError(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "execution reverted")
+}
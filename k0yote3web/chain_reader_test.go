@@ -0,0 +1,73 @@
+package k0yote3web
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubRevertError struct {
+	data string
+}
+
+func (stubRevertError) Error() string  { return "execution reverted" }
+func (stubRevertError) ErrorCode() int { return 3 }
+func (e stubRevertError) ErrorData() interface{} {
+	if e.data == "" {
+		return "0x"
+	}
+	return e.data
+}
+
+// stubTokenContract answers eth_call as an ERC-721 whose token 0 does not exist and
+// whose token 2 fails once with a transient error.
+type stubTokenContract struct {
+	erc721 abi.ABI
+	calls  map[int64]int
+}
+
+func (s *stubTokenContract) Call(args callArgs, block string) (hexutil.Bytes, error) {
+	values, err := s.erc721.Methods["tokenURI"].Inputs.Unpack(args.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	tokenID := values[0].(*big.Int).Int64()
+	s.calls[tokenID]++
+
+	switch {
+	case tokenID == 0:
+		return nil, stubRevertError{}
+	case tokenID == 2 && s.calls[tokenID] == 1:
+		return nil, errors.New("header not found")
+	case tokenID >= 3:
+		return s.erc721.Methods["tokenURI"].Outputs.Pack("https://example.com/unrevealed.json")
+	}
+
+	return s.erc721.Methods["tokenURI"].Outputs.Pack(fmt.Sprintf("https://example.com/%d.json", tokenID))
+}
+
+func TestChainReaderTokenURIs(t *testing.T) {
+	tokenABI, err := abi.JSON(strings.NewReader(erc721ABI))
+	assert.NoError(t, err)
+
+	stub := &stubTokenContract{erc721: tokenABI, calls: make(map[int64]int)}
+	helper := newStubContractHelper(t, stub)
+
+	reader, err := newChainReader(helper.ProviderHandler, &ChainReaderOptions{BatchSize: 2})
+	assert.NoError(t, err)
+
+	endpoints, filenames, err := discoverMetadataEndpoints(reader, "0x0000000000000000000000000000000000000001", 0, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/1.json", "https://example.com/2.json", "https://example.com/unrevealed.json"}, endpoints)
+	assert.Equal(t, []string{"3", "4"}, filenames["https://example.com/unrevealed.json"])
+
+	// reverted calls are not retried, transient failures are
+	assert.Equal(t, 1, stub.calls[0])
+	assert.Equal(t, 2, stub.calls[2])
+}
@@ -0,0 +1,224 @@
+package k0yote3web
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type NativeCurrency struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
+// Chain describes a network known to the SDK.
+type Chain struct {
+	Name    string
+	Aliases []string
+	ChainID int64
+	Testnet bool
+
+	NativeCurrency NativeCurrency
+	// ProviderNetworks is the network name used in the RPC url of each third party provider,
+	// empty for QuickNode Ethereum endpoints which have no network in their host
+	ProviderNetworks map[ThirdpartyProvider]string
+	// PublicRpcUrls are free endpoints usable without an api key
+	PublicRpcUrls []string
+	ExplorerURL   string
+	// EIP1559 tells whether the chain accepts dynamic fee transactions
+	EIP1559    bool
+	Multicall3 common.Address
+	// OPStack chains charge an L1 data fee on top of the gas, priced by the GasPriceOracle predeploy
+	OPStack bool
+}
+
+// gasPriceOracleAddress is the predeploy pricing the L1 data fee on OP stack chains
+var gasPriceOracleAddress = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+var ether = NativeCurrency{Name: "Ether", Symbol: "ETH", Decimals: 18}
+
+var chains = []Chain{
+	{
+		Name:             "ethereum",
+		Aliases:          []string{"mainnet", "eth"},
+		ChainID:          1,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "mainnet", ALCHEMY: "eth-mainnet", QUICKNODE: "", ANKR: "eth"},
+		PublicRpcUrls:    []string{"https://ethereum-rpc.publicnode.com"},
+		ExplorerURL:      "https://etherscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "sepolia",
+		Aliases:          []string{"eth-sepolia"},
+		ChainID:          11155111,
+		Testnet:          true,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "sepolia", ALCHEMY: "eth-sepolia", QUICKNODE: "ethereum-sepolia", ANKR: "eth_sepolia"},
+		PublicRpcUrls:    []string{"https://ethereum-sepolia-rpc.publicnode.com"},
+		ExplorerURL:      "https://sepolia.etherscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "holesky",
+		Aliases:          []string{"eth-holesky"},
+		ChainID:          17000,
+		Testnet:          true,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "holesky", ALCHEMY: "eth-holesky", QUICKNODE: "ethereum-holesky", ANKR: "eth_holesky"},
+		PublicRpcUrls:    []string{"https://ethereum-holesky-rpc.publicnode.com"},
+		ExplorerURL:      "https://holesky.etherscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "polygon",
+		Aliases:          []string{"matic", "polygon-mainnet"},
+		ChainID:          137,
+		NativeCurrency:   NativeCurrency{Name: "POL", Symbol: "POL", Decimals: 18},
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "polygon-mainnet", ALCHEMY: "polygon-mainnet", QUICKNODE: "matic", ANKR: "polygon"},
+		PublicRpcUrls:    []string{"https://polygon-rpc.com"},
+		ExplorerURL:      "https://polygonscan.com",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "polygon-amoy",
+		Aliases:          []string{"amoy"},
+		ChainID:          80002,
+		Testnet:          true,
+		NativeCurrency:   NativeCurrency{Name: "POL", Symbol: "POL", Decimals: 18},
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "polygon-amoy", ALCHEMY: "polygon-amoy", QUICKNODE: "matic-amoy", ANKR: "polygon_amoy"},
+		PublicRpcUrls:    []string{"https://rpc-amoy.polygon.technology"},
+		ExplorerURL:      "https://amoy.polygonscan.com",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "base",
+		Aliases:          []string{"base-mainnet"},
+		ChainID:          8453,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "base-mainnet", ALCHEMY: "base-mainnet", QUICKNODE: "base-mainnet", ANKR: "base"},
+		PublicRpcUrls:    []string{"https://mainnet.base.org"},
+		ExplorerURL:      "https://basescan.org",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+		OPStack:          true,
+	},
+	{
+		Name:             "base-sepolia",
+		ChainID:          84532,
+		Testnet:          true,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "base-sepolia", ALCHEMY: "base-sepolia", QUICKNODE: "base-sepolia", ANKR: "base_sepolia"},
+		PublicRpcUrls:    []string{"https://sepolia.base.org"},
+		ExplorerURL:      "https://sepolia.basescan.org",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+		OPStack:          true,
+	},
+	{
+		Name:             "arbitrum",
+		Aliases:          []string{"arbitrum-one", "arbitrum-mainnet"},
+		ChainID:          42161,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "arbitrum-mainnet", ALCHEMY: "arb-mainnet", QUICKNODE: "arbitrum-mainnet", ANKR: "arbitrum"},
+		PublicRpcUrls:    []string{"https://arb1.arbitrum.io/rpc"},
+		ExplorerURL:      "https://arbiscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "arbitrum-sepolia",
+		ChainID:          421614,
+		Testnet:          true,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "arbitrum-sepolia", ALCHEMY: "arb-sepolia", QUICKNODE: "arbitrum-sepolia", ANKR: "arbitrum_sepolia"},
+		PublicRpcUrls:    []string{"https://sepolia-rollup.arbitrum.io/rpc"},
+		ExplorerURL:      "https://sepolia.arbiscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+	},
+	{
+		Name:             "optimism",
+		Aliases:          []string{"op", "optimism-mainnet"},
+		ChainID:          10,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "optimism-mainnet", ALCHEMY: "opt-mainnet", QUICKNODE: "optimism", ANKR: "optimism"},
+		PublicRpcUrls:    []string{"https://mainnet.optimism.io"},
+		ExplorerURL:      "https://optimistic.etherscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+		OPStack:          true,
+	},
+	{
+		Name:             "optimism-sepolia",
+		Aliases:          []string{"op-sepolia"},
+		ChainID:          11155420,
+		Testnet:          true,
+		NativeCurrency:   ether,
+		ProviderNetworks: map[ThirdpartyProvider]string{INFURA: "optimism-sepolia", ALCHEMY: "opt-sepolia", QUICKNODE: "optimism-sepolia", ANKR: "optimism_sepolia"},
+		PublicRpcUrls:    []string{"https://sepolia.optimism.io"},
+		ExplorerURL:      "https://sepolia-optimism.etherscan.io",
+		EIP1559:          true,
+		Multicall3:       multicall3Address,
+		OPStack:          true,
+	},
+}
+
+// Chains returns the networks known to the SDK.
+func Chains() []Chain {
+	return append([]Chain(nil), chains...)
+}
+
+// GetChain looks a chain up by name, alias or decimal chain ID.
+func GetChain(nameOrID string) (*Chain, error) {
+	key := strings.ToLower(strings.TrimSpace(nameOrID))
+	if id, err := strconv.ParseInt(key, 10, 64); err == nil {
+		return GetChainByID(big.NewInt(id))
+	}
+
+	for i := range chains {
+		if chains[i].Name == key {
+			return &chains[i], nil
+		}
+		for _, alias := range chains[i].Aliases {
+			if alias == key {
+				return &chains[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown chain: %s", nameOrID)
+}
+
+func GetChainByID(chainID *big.Int) (*Chain, error) {
+	for i := range chains {
+		if big.NewInt(chains[i].ChainID).Cmp(chainID) == 0 {
+			return &chains[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown chain ID: %v", chainID)
+}
+
+func (c *Chain) GetChainID() *big.Int {
+	return big.NewInt(c.ChainID)
+}
+
+// TxURL is the explorer page of a transaction.
+func (c *Chain) TxURL(hash common.Hash) string {
+	return fmt.Sprintf("%s/tx/%s", c.ExplorerURL, hash.Hex())
+}
+
+// AddressURL is the explorer page of an account or contract.
+func (c *Chain) AddressURL(address common.Address) string {
+	return fmt.Sprintf("%s/address/%s", c.ExplorerURL, address.Hex())
+}
@@ -0,0 +1,44 @@
+package k0yote3web
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChain(t *testing.T) {
+	for _, key := range []string{"polygon", "Matic", "137"} {
+		chain, err := GetChain(key)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(137), chain.ChainID)
+	}
+
+	chain, err := GetChainByID(big.NewInt(84532))
+	assert.NoError(t, err)
+	assert.Equal(t, "base-sepolia", chain.Name)
+	assert.True(t, chain.Testnet)
+
+	_, err = GetChain("polygon-mumbai")
+	assert.ErrorContains(t, err, "unknown chain")
+}
+
+func TestProviderHandlerChainValidation(t *testing.T) {
+	endpoint := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 1})
+	client, err := ethclient.Dial(endpoint.URL)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	handler, err := NewProviderHandler(client, "")
+	assert.NoError(t, err)
+
+	chain, err := handler.GetChain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "polygon", chain.Name)
+
+	handler.chain, _ = GetChain("polygon-amoy")
+	_, err = handler.GetChainID(context.Background())
+	assert.ErrorContains(t, err, "provider serves chain ID 137, expected polygon-amoy (80002)")
+}
@@ -3,6 +3,7 @@ package k0yote3web
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/url"
 	"os"
@@ -11,9 +12,13 @@ import (
 	"strings"
 )
 
+// getSavePath returns the folder below the root of the module, creating it when it does not
+// exist. Absolute folders are used as they are.
 func getSavePath(folderName string) (string, error) {
-	wd := rootDir()
-	tmpDir := path.Join(wd, folderName)
+	tmpDir := folderName
+	if !filepath.IsAbs(folderName) {
+		tmpDir = path.Join(rootDir(), folderName)
+	}
 	if f, err := os.Stat(tmpDir); os.IsNotExist(err) || !f.IsDir() {
 		if err := os.Mkdir(tmpDir, 0777); err != nil {
 			return "", err
@@ -56,9 +61,62 @@ func getFilename(endpoint string) (string, error) {
 	path := u.Path
 	segments := strings.Split(path, "/")
 
+	// ipfs://<cid> and ar://<id> name the file by their host
+	if segments[len(segments)-1] == "" && (u.Scheme == "ipfs" || u.Scheme == "ar") {
+		return u.Host, nil
+	}
+
 	return segments[len(segments)-1], nil
 }
 
+// tokenID is the name of a metadata file without its extension.
+func tokenID(file string) string {
+	return file[:len(file)-len(filepath.Ext(file))]
+}
+
+// MetaDataFile is the metadata of a token read from File, the name of its json file.
+type MetaDataFile struct {
+	File string
+	MetaData
+}
+
+// ReadMetaDir reads every metadata file of inputDir, internal/meta by default, in file name order.
+func ReadMetaDir(inputDir string) ([]*MetaDataFile, error) {
+	if len(inputDir) == 0 {
+		inputDir = metadataFolderName
+	}
+
+	metaDir, err := getSavePath(inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metaFiles, err := os.ReadDir(metaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*MetaDataFile, 0, len(metaFiles))
+	for _, metaFile := range metaFiles {
+		if metaFile.IsDir() {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(metaDir, metaFile.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := &MetaDataFile{File: metaFile.Name()}
+		if err := json.Unmarshal(b, &m.MetaData); err != nil {
+			return nil, fmt.Errorf("%s: %w", metaFile.Name(), err)
+		}
+		metas = append(metas, m)
+	}
+
+	return metas, nil
+}
+
 func saveJson(data []byte, savePath, endpoint string) error {
 
 	filename, err := getFilename(endpoint)
@@ -86,7 +144,7 @@ func saveJson(data []byte, savePath, endpoint string) error {
 }
 
 func saveImage(data []byte, savePath, endpoint string) error {
-	filename, err := getFilename(endpoint)
+	filename, err := imageFilename(endpoint)
 	if err != nil {
 		return err
 	}
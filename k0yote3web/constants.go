@@ -14,7 +14,13 @@ const (
 	imageFolderName    = saveFolderName + "/" + "image"
 	uploadFolderName   = saveFolderName + "/" + "upload"
 
+	placeholderFolderName = saveFolderName + "/" + "placeholder"
+	revealFolderName      = saveFolderName + "/" + "reveal"
+	provenanceFileName    = "provenance.json"
+	revealFileName        = "reveal.json"
+
 	fetchDownloadMetaLimit  = 300
 	fetchDownloadImageLimit = 30
 	waitTime                = 1 * time.Second
+	downloadTimeout         = 60 * time.Second
 )
@@ -1,17 +1,17 @@
 package k0yote3web
 
-import "github.com/ethereum/go-ethereum/ethclient"
-
 type ContractDeployer struct {
 	*ProviderHandler
 	helper *contractHelper
 }
 
-func newContractDeployer(provider *ethclient.Client, privateKey string) (*ContractDeployer, error) {
+func newContractDeployer(provider Provider, privateKey string, nonces *NonceManager) (*ContractDeployer, error) {
 	handler, err := NewProviderHandler(provider, privateKey)
 	if err != nil {
 		return nil, err
 	}
+	// Share the nonces with the SDK handler so both never hand out the same nonce
+	handler.nonces = nonces
 
 	helper, err := newContractHelper(handler)
 	if err != nil {
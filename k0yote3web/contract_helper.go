@@ -4,9 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math/big"
 	"strings"
-	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -37,54 +37,34 @@ func newContractHelper(handler *ProviderHandler) (*contractHelper, error) {
 }
 
 func (c *contractHelper) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return c.WaitForReceipt(ctx, txHash, 1)
+}
+
+// WaitForReceipt waits until txHash is mined and the given number of blocks, counting its
+// own, have been added on top of it. The wait honors the deadline of ctx.
+func (c *contractHelper) WaitForReceipt(ctx context.Context, txHash common.Hash, confirmations uint64) (*types.Receipt, error) {
 	zeroHash := common.Hash{}
 	if txHash == zeroHash {
 		return nil, fmt.Errorf("malformed transaction hash: [%v]", txHash)
 	}
 
-	var err error
-	type ReceiptCh struct {
-		ret *types.Receipt
-		err error
+	receipt, err := c.waitMined(ctx, []common.Hash{txHash}, confirmations)
+	if errors.Is(err, context.DeadlineExceeded) {
+		c.resyncDropped(txHash)
+		return nil, fmt.Errorf("transaction was not mined within the deadline, "+
+			"please make sure your transaction was properly sent. Be aware that it might still be mined: %w", err)
 	}
 
-	provider := c.GetProvider()
-	var timeoutFlag int32
-	ch := make(chan *ReceiptCh, 1)
-
-	go func() {
-		for {
-			receipt, err := provider.TransactionReceipt(ctx, txHash)
-			if err != nil && err.Error() != "not found" {
-				ch <- &ReceiptCh{
-					err: err,
-				}
-				break
-			}
-			if receipt != nil {
-				ch <- &ReceiptCh{
-					ret: receipt,
-					err: nil,
-				}
-				break
-			}
-			if atomic.LoadInt32(&timeoutFlag) == 1 {
-				break
-			}
-		}
-	}()
-
-	select {
-	case result := <-ch:
-		if result.err != nil {
-			return nil, err
-		}
+	return receipt, err
+}
 
-		return result.ret, nil
-	case <-time.After(time.Duration(txPollTimeout) * txWaitTimeBetweenAttempts):
-		atomic.StoreInt32(&timeoutFlag, 1)
-		return nil, fmt.Errorf("transaction was not mined within %v seconds, "+
-			"please make sure your transaction was properly sent. Be aware that it might still be mined", 720)
+// resyncDropped resyncs the nonces of the sender of a transaction that was not mined in time,
+// so that the nonce of a transaction dropped from the pool is handed out again instead of
+// leaving every later transaction stuck behind the gap.
+func (c *contractHelper) resyncDropped(hash common.Hash) {
+	// The context of the wait is done already
+	if err := c.nonces.ResyncSender(context.Background(), c.provider, hash); err != nil {
+		log.Printf("failed to resync the nonces after %s: %v\n", hash.Hex(), err)
 	}
 }
 
@@ -134,6 +114,56 @@ func (c *contractHelper) DeployCreate2(factoryContract common.Address, contractA
 // transact executes an actual transaction invocation, first deriving any missing
 // authorization fields, and then scheduling the transaction for execution.
 func (c *contractHelper) transact(opts *bind.TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
+	if c.dryRun != nil {
+		noSend := *opts
+		noSend.NoSend = true
+		opts = &noSend
+	}
+	signedTx, err := c.signTransaction(opts, contract, input)
+	if err != nil {
+		if c.dryRun != nil {
+			return nil, c.recordFailedDryRun(opts, contract, input, err)
+		}
+		return nil, err
+	}
+	if c.dryRun != nil {
+		// A dry run never uses the nonce
+		c.releaseNonce(opts, signedTx)
+		return c.simulateDryRun(opts, signedTx)
+	}
+	if opts.NoSend {
+		c.trackNonce(opts, signedTx)
+		return signedTx, nil
+	}
+	err = c.sendTransaction(opts.Context, signedTx)
+	if isNonceTooLow(err) && opts.Nonce == nil {
+		// The nonce was used outside of this manager, resync and retry once
+		if err := c.nonces.Resync(opts.Context, c.provider, opts.From); err != nil {
+			return nil, err
+		}
+		if signedTx, err = c.signTransaction(opts, contract, input); err != nil {
+			return nil, err
+		}
+		err = c.sendTransaction(opts.Context, signedTx)
+	}
+	if err != nil {
+		c.releaseNonce(opts, signedTx)
+		return nil, err
+	}
+	c.trackNonce(opts, signedTx)
+	return signedTx, nil
+}
+
+// signTransaction creates the transaction with any missing fields derived and signs it.
+func (c *contractHelper) signTransaction(opts *bind.TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
+	if c.gas != nil {
+		// Price the transaction with the gas strategy unless fees were given
+		priced, err := c.gas.Apply(opts)
+		if err != nil {
+			return nil, err
+		}
+		opts = priced
+	}
 	if opts.GasPrice != nil && (opts.GasFeeCap != nil || opts.GasTipCap != nil) {
 		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
 	}
@@ -160,25 +190,21 @@ func (c *contractHelper) transact(opts *bind.TransactOpts, contract *common.Addr
 	if err != nil {
 		return nil, err
 	}
-	// Sign the transaction and schedule it for execution
+	// Sign the transaction
 	if opts.Signer == nil {
+		c.releaseNonce(opts, rawTx)
 		return nil, fmt.Errorf("no signer to authorize the transaction with")
 	}
 	signedTx, err := opts.Signer(opts.From, rawTx)
 	if err != nil {
-		return nil, err
-	}
-	if opts.NoSend {
-		return signedTx, nil
-	}
-	if err := c.sendTransaction(opts.Context, signedTx); err != nil {
+		c.releaseNonce(opts, rawTx)
 		return nil, err
 	}
 	return signedTx, nil
 }
 
 func (c *contractHelper) sendTransaction(ctx context.Context, signedTx *types.Transaction) error {
-	return c.provider.SendTransaction(ensureContext(ctx), signedTx)
+	return callError(c.provider.SendTransaction(ensureContext(ctx), signedTx))
 }
 
 func (c *contractHelper) createLegacyTx(opts *bind.TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
@@ -277,7 +303,7 @@ func (c *contractHelper) createDynamicTx(opts *bind.TransactOpts, contract *comm
 }
 
 func (c *contractHelper) estimateGasLimit(opts *bind.TransactOpts, contract *common.Address, input []byte, gasPrice, gasTipCap, gasFeeCap, value *big.Int) (uint64, error) {
-	if contract != nil {
+	if contract != nil && len(input) > 0 {
 		addr := *contract
 		// Gas estimation cannot succeed without code for method invocations.
 		if code, err := c.provider.PendingCodeAt(ensureContext(opts.Context), addr); err != nil {
@@ -295,81 +321,185 @@ func (c *contractHelper) estimateGasLimit(opts *bind.TransactOpts, contract *com
 		Value:     value,
 		Data:      input,
 	}
-	return c.provider.EstimateGas(ensureContext(opts.Context), msg)
+	gasLimit, err := c.provider.EstimateGas(ensureContext(opts.Context), msg)
+	return gasLimit, callError(err)
 }
 
 func (c *contractHelper) getNonce(opts *bind.TransactOpts) (uint64, error) {
 	if opts.Nonce == nil {
-		return c.provider.PendingNonceAt(ensureContext(opts.Context), opts.From)
+		return c.nonces.Next(opts.Context, c.provider, opts.From)
 	} else {
 		return opts.Nonce.Uint64(), nil
 	}
 }
 
+// trackNonce records tx as in flight when its nonce was handed out by the nonce manager.
+func (c *contractHelper) trackNonce(opts *bind.TransactOpts, tx *types.Transaction) {
+	if opts.Nonce == nil {
+		c.nonces.Track(opts.From, tx.Nonce(), tx.Hash())
+	}
+}
+
+// releaseNonce gives back the nonce of a transaction that was never broadcast.
+func (c *contractHelper) releaseNonce(opts *bind.TransactOpts, tx *types.Transaction) {
+	if opts.Nonce == nil {
+		c.nonces.Release(opts.From, tx.Nonce())
+	}
+}
+
+// createRawTransaction creates a transfer of the whole balance of opts.From to the address,
+// less the most the transaction can cost: the gas at the fee cap (or gas price) and, on OP
+// stack chains, the L1 data fee. Whatever the fee cap exceeds the price paid by stays in the account.
 func (c *contractHelper) createRawTransaction(opts *bind.TransactOpts, to *common.Address) (*types.Transaction, error) {
-	head, err := c.provider.HeaderByNumber(ensureContext(opts.Context), nil)
+	ctx := ensureContext(opts.Context)
+	if opts.GasPrice != nil && (opts.GasFeeCap != nil || opts.GasTipCap != nil) {
+		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	if c.gas != nil {
+		priced, err := c.gas.Apply(opts)
+		if err != nil {
+			return nil, err
+		}
+		opts = priced
+	}
+
+	balance, err := c.provider.BalanceAt(ctx, opts.From, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	gasTipCap, err := c.provider.SuggestGasTipCap(ensureContext(opts.Context))
+	// Estimated rather than 21000 as transfers to contracts and on Arbitrum cost more
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		if gasLimit, err = c.provider.EstimateGas(ctx, ethereum.CallMsg{From: opts.From, To: to}); err != nil {
+			return nil, err
+		}
+	}
+
+	head, err := c.provider.HeaderByNumber(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	gasFeeCap := new(big.Int).Add(
-		gasTipCap,
-		new(big.Int).Mul(head.BaseFee, big.NewInt(basefeeWiggleMultiplier)),
+	var (
+		baseTx types.TxData
+		fee    *big.Int
 	)
-
-	if gasFeeCap.Cmp(gasTipCap) < 0 {
-		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+	if opts.GasPrice != nil || (head.BaseFee == nil && opts.GasFeeCap == nil) {
+		gasPrice := opts.GasPrice
+		if gasPrice == nil {
+			if gasPrice, err = c.provider.SuggestGasPrice(ctx); err != nil {
+				return nil, err
+			}
+		}
+		fee = new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice)
+		baseTx = &types.LegacyTx{To: to, GasPrice: gasPrice, Gas: gasLimit, Value: balance}
+	} else {
+		gasTipCap := opts.GasTipCap
+		if gasTipCap == nil {
+			if gasTipCap, err = c.provider.SuggestGasTipCap(ctx); err != nil {
+				return nil, err
+			}
+		}
+		gasFeeCap := opts.GasFeeCap
+		if gasFeeCap == nil {
+			gasFeeCap = new(big.Int).Add(
+				gasTipCap,
+				new(big.Int).Mul(head.BaseFee, big.NewInt(basefeeWiggleMultiplier)),
+			)
+		}
+		if gasFeeCap.Cmp(gasTipCap) < 0 {
+			return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+		}
+		fee = new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasFeeCap)
+		baseTx = &types.DynamicFeeTx{To: to, GasTipCap: gasTipCap, GasFeeCap: gasFeeCap, Gas: gasLimit, Value: balance}
 	}
 
-	estimatteFee := estimateGasFee(head.BaseFee, gasTipCap, gasFeeCap, Low)
-	value := new(big.Int).Sub(opts.Value, estimatteFee)
-
-	if value.Cmp(big.NewInt(0)) <= 0 {
-		return nil, fmt.Errorf("not enough fund: gas price + value")
+	l1Fee, err := c.l1Fee(ctx, types.NewTx(baseTx))
+	if err != nil {
+		return nil, err
 	}
+	fee.Add(fee, l1Fee)
 
-	opts.Value = value
+	value := new(big.Int).Sub(balance, fee)
+	if value.Sign() <= 0 {
+		return nil, fmt.Errorf("not enough fund: balance %v does not cover the fee %v", balance, fee)
+	}
 
 	nonce, err := c.getNonce(opts)
 	if err != nil {
 		return nil, err
 	}
-	baseTx := &types.DynamicFeeTx{
-		To:        to,
-		Nonce:     nonce,
-		GasFeeCap: gasFeeCap,
-		GasTipCap: gasTipCap,
-		Gas:       uint64(21_000),
-		Value:     value,
-		Data:      nil,
+	switch tx := baseTx.(type) {
+	case *types.LegacyTx:
+		tx.Nonce, tx.Value = nonce, value
+	case *types.DynamicFeeTx:
+		tx.Nonce, tx.Value = nonce, value
 	}
 
 	return types.NewTx(baseTx), nil
 }
 
-func estimateGasFee(baseFee, gasTipCap, gasFeeCap *big.Int, priority GasPriority) *big.Int {
-	/*
-		Here is an example:
-		Your account balance: 1 ETH
-		Estimated gas cost: 21,000 gas
-		Current base fee: 20 Gwei
-		Maximum priority fee you are willing to pay: 50 Gwei
-		Maximum amount of Ether to send = 1 ETH - (21,000 gas * (20 Gwei + 50 Gwei)) = 1 ETH - 0.00147 ETH = 0.99853 ETH
-	*/
-	gasLimit := big.NewInt(21_000)
-	return new(big.Int).Mul(gasLimit, new(big.Int).Add(baseFeeByPriority(baseFee, priority), gasTipCap))
+// l1Fee is the L1 data fee of tx on OP stack chains, with a 10% margin for the L1 base
+// fee to change until it is included. It is 0 on other chains.
+func (c *contractHelper) l1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	chainID, err := c.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chain, err := GetChainByID(chainID); err != nil || !chain.OPStack {
+		return new(big.Int), nil
+	}
+
+	oracle, err := abi.JSON(strings.NewReader(gasPriceOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	// The fee grows with the size of the transaction, which is the largest with the whole balance as value
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	input, err := oracle.Pack("getL1Fee", unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.provider.CallContract(ctx, ethereum.CallMsg{To: &gasPriceOracleAddress, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, err := oracle.Unpack("getL1Fee", output)
+	if err != nil {
+		return nil, err
+	}
+
+	return bumpFee(values[0].(*big.Int), 10), nil
 }
 
-func baseFeeByPriority(baseFee *big.Int, priority GasPriority) *big.Int {
-	bf := FromWeiWithUnit(baseFee, EtherUnitGWei)
-	x := new(big.Float).Mul(bf, big.NewFloat(priority.Value()))
-	f, _ := x.Float64()
-	return ToGWei(f)
+// sendRawTransaction signs rawTx and broadcasts it unless opts.NoSend is set.
+func (c *contractHelper) sendRawTransaction(opts *bind.TransactOpts, rawTx *types.Transaction) (*types.Transaction, error) {
+	if opts.Signer == nil {
+		c.releaseNonce(opts, rawTx)
+		return nil, fmt.Errorf("no signer to authorize the transaction with")
+	}
+	signedTx, err := opts.Signer(opts.From, rawTx)
+	if err != nil {
+		c.releaseNonce(opts, rawTx)
+		return nil, err
+	}
+	if c.dryRun != nil {
+		c.releaseNonce(opts, rawTx)
+		return c.simulateDryRun(opts, signedTx)
+	}
+	if !opts.NoSend {
+		if err := c.sendTransaction(opts.Context, signedTx); err != nil {
+			c.releaseNonce(opts, rawTx)
+			return nil, err
+		}
+	}
+	c.trackNonce(opts, signedTx)
+	return signedTx, nil
 }
 
 // ensureContext is a helper method to ensure a context is not nil, even if the
@@ -0,0 +1,96 @@
+package k0yote3web
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// dataURIExtensions are the extensions of the media types of on-chain art, mime has several
+// for some of them
+var dataURIExtensions = map[string]string{
+	"application/json": ".json",
+	"image/svg+xml":    ".svg",
+	"image/png":        ".png",
+	"image/jpeg":       ".jpg",
+	"image/gif":        ".gif",
+	"image/webp":       ".webp",
+	"image/avif":       ".avif",
+	"text/html":        ".html",
+	"text/plain":       ".txt",
+}
+
+type dataURI struct {
+	// MediaType is the media type without its parameters, text/plain when the uri has none
+	MediaType string
+	Data      []byte
+}
+
+func isDataURI(uri string) bool {
+	return strings.HasPrefix(uri, "data:")
+}
+
+// parseDataURI decodes a data:[<media type>][;base64],<data> uri. Data without base64 is
+// percent-encoded, or raw utf8 as some contracts return it.
+func parseDataURI(uri string) (*dataURI, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !isDataURI(uri) || !ok {
+		return nil, fmt.Errorf("invalid data uri: %.32s", uri)
+	}
+
+	params := strings.Split(header, ";")
+	isBase64 := params[len(params)-1] == "base64"
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+	if mediaType == "" || mediaType == "base64" {
+		mediaType = "text/plain"
+	}
+
+	if isBase64 {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			// some encoders drop the padding
+			if data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "=")); err != nil {
+				return nil, fmt.Errorf("invalid base64 data uri: %w", err)
+			}
+		}
+		return &dataURI{MediaType: mediaType, Data: data}, nil
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		data = payload
+	}
+
+	return &dataURI{MediaType: mediaType, Data: []byte(data)}, nil
+}
+
+// extension returns the file extension of the media type, .bin when it is unknown.
+func (d *dataURI) extension() string {
+	if ext, ok := dataURIExtensions[d.MediaType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(d.MediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// imageFilename returns the name an image is saved as: the last segment of its url, or the
+// hash of the content with the extension of its media type for a data uri.
+func imageFilename(image string) (string, error) {
+	if !isDataURI(image) {
+		return getFilename(image)
+	}
+
+	d, err := parseDataURI(image)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(d.Data)
+	return hex.EncodeToString(hash[:8]) + d.extension(), nil
+}
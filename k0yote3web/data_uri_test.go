@@ -0,0 +1,42 @@
+package k0yote3web
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDataURI(t *testing.T) {
+	meta := `{"name":"Foo #1","image":"data:image/svg+xml;utf8,<svg></svg>"}`
+	d, err := parseDataURI("data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(meta)))
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", d.MediaType)
+	assert.Equal(t, meta, string(d.Data))
+
+	d, err = parseDataURI("data:image/svg+xml;charset=utf-8,%3Csvg%3E%3C%2Fsvg%3E")
+	assert.NoError(t, err)
+	assert.Equal(t, "<svg></svg>", string(d.Data))
+	assert.Equal(t, ".svg", d.extension())
+
+	d, err = parseDataURI(`data:application/json;utf8,{"name":"100% on-chain"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"100% on-chain"}`, string(d.Data))
+
+	_, err = parseDataURI("data:image/png;base64")
+	assert.Error(t, err)
+
+	b, err := downloadFile("data:,hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+}
+
+func TestImageFilename(t *testing.T) {
+	filename, err := imageFilename("https://example.com/images/1.png?size=large")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.png", filename)
+
+	filename, err = imageFilename("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte("<svg></svg>")))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{16}\.svg$`, filename)
+}
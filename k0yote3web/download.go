@@ -11,11 +11,12 @@ import (
 type Download struct {
 	downloadHelper *downloadHelper
 	imgHelper      *imageHelper
+	resolver       *uriResolver
 }
 
-func newDownload(opts *DownloadMetaOptions) (*Download, error) {
+func newDownload(opts *DownloadMetaOptions, reader *ChainReader) (*Download, error) {
 
-	helper, err := newDownloadHelper(opts)
+	helper, err := newDownloadHelper(opts, reader)
 	if err != nil {
 		return nil, err
 	}
@@ -25,9 +26,25 @@ func newDownload(opts *DownloadMetaOptions) (*Download, error) {
 		return nil, err
 	}
 
+	var ipfsGateways, arweaveGateways []string
+	var node *ipfsFetcher
+	if opts != nil {
+		ipfsGateways = opts.IpfsGateways
+		arweaveGateways = opts.ArweaveGateways
+		if opts.IpfsNode != nil {
+			if node, err = newIpfsFetcher(opts.IpfsNode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resolver := newURIResolver(ipfsGateways, arweaveGateways)
+	resolver.node = node
+
 	return &Download{
 		downloadHelper: helper,
 		imgHelper:      imgHelper,
+		resolver:       resolver,
 	}, nil
 }
 
@@ -44,14 +61,16 @@ func (d Download) DownloadAndSaveMetadata() error {
 	for i := 1; i <= maxPage; i++ {
 		endpoints, _, _ := pagination(d.downloadHelper.endpoints, i, fetchDownloadMetaLimit)
 
-		downloadList, err := downloadMultipleFiles(endpoints)
+		downloadList, err := downloadMultipleFiles(d.resolver, endpoints)
 		if err != nil {
 			return err
 		}
 
 		for _, download := range downloadList {
-			if err := saveJson(download.Data, savePath, download.Endpoint); err != nil {
-				return err
+			for _, filename := range d.downloadHelper.savedFilenames(download.Endpoint) {
+				if err := saveJson(download.Data, savePath, filename); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -84,7 +103,7 @@ func (d Download) DownloadAndSaveImage() error {
 	for i := 1; i <= maxPage; i++ {
 		endpoints, _, _ := pagination(d.imgHelper.endpoints, i, fetchDownloadImageLimit)
 
-		downloadList, err := downloadMultipleFiles(endpoints)
+		downloadList, err := downloadMultipleFiles(d.resolver, endpoints)
 		if err != nil {
 			return err
 		}
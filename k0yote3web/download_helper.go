@@ -1,25 +1,37 @@
 package k0yote3web
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math/big"
 	"net/http"
 	"net/url"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type downloadHelper struct {
 	endpoints []string
+	// filenames maps endpoints discovered on chain to the token ids they are saved as
+	filenames map[string][]string
 }
 
-func newDownloadHelper(opts *DownloadMetaOptions) (*downloadHelper, error) {
+func newDownloadHelper(opts *DownloadMetaOptions, reader *ChainReader) (*downloadHelper, error) {
 	var (
 		endpoints = make([]string, 0)
+		filenames = make(map[string][]string)
 		err       error
 	)
 
 	if opts != nil {
-		endpoints, err = makeMetadataEndpointList(opts.BaseURL, opts.StartTokenID, opts.EndTokenID)
+		if opts.ContractAddress != "" {
+			endpoints, filenames, err = discoverMetadataEndpoints(reader, opts.ContractAddress, opts.StartTokenID, opts.EndTokenID)
+		} else {
+			endpoints, err = makeMetadataEndpointList(opts.BaseURL, opts.StartTokenID, opts.EndTokenID)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -27,15 +39,25 @@ func newDownloadHelper(opts *DownloadMetaOptions) (*downloadHelper, error) {
 
 	return &downloadHelper{
 		endpoints: endpoints,
+		filenames: filenames,
 	}, nil
 }
 
-func downloadMultipleFiles(endpoints []string) ([]DownloadCh, error) {
+// savedFilenames returns the names the metadata downloaded from endpoint is saved as.
+func (h *downloadHelper) savedFilenames(endpoint string) []string {
+	if filenames, ok := h.filenames[endpoint]; ok {
+		return filenames
+	}
+
+	return []string{endpoint}
+}
+
+func downloadMultipleFiles(resolver *uriResolver, endpoints []string) ([]DownloadCh, error) {
 	done := make(chan DownloadCh, len(endpoints))
 	errch := make(chan error, len(endpoints))
 	for _, endpoint := range endpoints {
 		go func(endpoint string) {
-			b, err := downloadFile(endpoint)
+			b, err := resolver.download(endpoint)
 			if err != nil {
 				errch <- err
 				done <- DownloadCh{}
@@ -68,6 +90,15 @@ func downloadMultipleFiles(endpoints []string) ([]DownloadCh, error) {
 }
 
 func downloadFile(endpoint string) ([]byte, error) {
+	// on-chain metadata and art is inlined in the uri
+	if isDataURI(endpoint) {
+		d, err := parseDataURI(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return d.Data, nil
+	}
+
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -76,7 +107,7 @@ func downloadFile(endpoint string) ([]byte, error) {
 	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
 	req.Header.Add("Accept", "application/json")
 
-	client := http.Client{}
+	client := http.Client{Timeout: downloadTimeout}
 	response, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -110,3 +141,41 @@ func makeMetadataEndpointList(baseURL string, startTokenID int, endTokenID int)
 
 	return endpoints, nil
 }
+
+// discoverMetadataEndpoints reads tokenURI of every token in the range, skipping the
+// tokens whose tokenURI reverts (e.g. burned or not minted yet).
+func discoverMetadataEndpoints(reader *ChainReader, contractAddress string, startTokenID int, endTokenID int) ([]string, map[string][]string, error) {
+	if reader == nil {
+		return nil, nil, errors.New("a chain reader is required to discover metadata from a contract")
+	}
+	if !common.IsHexAddress(contractAddress) {
+		return nil, nil, fmt.Errorf("invalid contract address: %s", contractAddress)
+	}
+
+	tokenIDs := []*big.Int{}
+	for i := startTokenID; i <= endTokenID; i++ {
+		tokenIDs = append(tokenIDs, big.NewInt(int64(i)))
+	}
+
+	results, err := reader.TokenURIs(context.Background(), common.HexToAddress(contractAddress), tokenIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoints := []string{}
+	filenames := make(map[string][]string)
+	for i, result := range results {
+		if !result.Success {
+			log.Printf("skipped token %v: %v\n", tokenIDs[i], result.Err)
+			continue
+		}
+
+		endpoint := result.Value.(string)
+		if _, ok := filenames[endpoint]; !ok {
+			endpoints = append(endpoints, endpoint)
+		}
+		filenames[endpoint] = append(filenames[endpoint], tokenIDs[i].String())
+	}
+
+	return endpoints, filenames, nil
+}
@@ -0,0 +1,151 @@
+package k0yote3web
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DryRunReport describes a transaction that was simulated instead of broadcast.
+type DryRunReport struct {
+	From  common.Address
+	To    *common.Address
+	Nonce uint64
+	Value *big.Int
+	Data  []byte
+	Gas   uint64
+	// GasPrice is the price expected to be paid per gas, the base fee plus the tip on EIP-1559 chains
+	GasPrice *big.Int
+	// ExpectedCost is Gas at GasPrice, MaxCost is Gas at the fee cap
+	ExpectedCost *big.Int
+	MaxCost      *big.Int
+	// Result is the return data of the simulation at the pending block
+	Result []byte
+	// Err is why the transaction would fail, with the revert reason decoded
+	Err error
+}
+
+func (r *DryRunReport) String() string {
+	var b strings.Builder
+	to := "contract creation"
+	if r.To != nil {
+		to = r.To.Hex()
+	}
+	fmt.Fprintf(&b, "from: [%s] to: [%s] nonce: [%d] value: [%s]\n", r.From.Hex(), to, r.Nonce, ToEther(r.Value).Text('f', 18))
+	fmt.Fprintf(&b, "calldata: [%s]\n", hexutil.Encode(r.Data))
+	if r.Err != nil {
+		fmt.Fprintf(&b, "would fail: [%v]", r.Err)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "gas: [%d] gasPrice: [%s gwei]\n", r.Gas, FromWeiWithUnit(r.GasPrice, EtherUnitGWei).Text('f', 9))
+	fmt.Fprintf(&b, "expected cost: [%s] max cost: [%s]", ToEther(r.ExpectedCost).Text('f', 18), ToEther(r.MaxCost).Text('f', 18))
+
+	return b.String()
+}
+
+type dryRunRecorder struct {
+	mu      sync.Mutex
+	reports []*DryRunReport
+}
+
+// SetDryRun makes every write simulate its transaction at the pending block and log a
+// report instead of broadcasting it.
+func (handler *ProviderHandler) SetDryRun(enabled bool) {
+	if !enabled {
+		handler.dryRun = nil
+	} else if handler.dryRun == nil {
+		handler.dryRun = &dryRunRecorder{}
+	}
+}
+
+func (handler *ProviderHandler) IsDryRun() bool {
+	return handler.dryRun != nil
+}
+
+// DryRunReports returns the reports of the transactions simulated so far.
+func (handler *ProviderHandler) DryRunReports() []*DryRunReport {
+	if handler.dryRun == nil {
+		return nil
+	}
+
+	handler.dryRun.mu.Lock()
+	defer handler.dryRun.mu.Unlock()
+
+	return append([]*DryRunReport(nil), handler.dryRun.reports...)
+}
+
+func (handler *ProviderHandler) recordDryRun(report *DryRunReport) {
+	handler.dryRun.mu.Lock()
+	handler.dryRun.reports = append(handler.dryRun.reports, report)
+	handler.dryRun.mu.Unlock()
+
+	log.Printf("dry run, transaction not sent:\n%s\n", report)
+}
+
+// simulateDryRun calls tx at the pending block and records the report of the dry run.
+func (c *contractHelper) simulateDryRun(opts *bind.TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	ctx := ensureContext(opts.Context)
+	report := &DryRunReport{
+		From:  opts.From,
+		To:    tx.To(),
+		Nonce: tx.Nonce(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+		Gas:   tx.Gas(),
+	}
+
+	head, err := c.provider.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	report.GasPrice = tx.GasPrice()
+	if tx.Type() != types.LegacyTxType && head.BaseFee != nil {
+		report.GasPrice = bigMin(new(big.Int).Add(head.BaseFee, tx.GasTipCap()), tx.GasFeeCap())
+	}
+	gas := new(big.Int).SetUint64(tx.Gas())
+	report.ExpectedCost = new(big.Int).Mul(gas, report.GasPrice)
+	report.MaxCost = new(big.Int).Mul(gas, tx.GasFeeCap())
+
+	report.Result, err = c.provider.PendingCallContract(ctx, ethereum.CallMsg{
+		From:  opts.From,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	})
+	report.Err = callError(err)
+	c.recordDryRun(report)
+
+	if report.Err != nil {
+		return nil, report.Err
+	}
+	return tx, nil
+}
+
+// recordFailedDryRun reports a transaction that could not even be built, e.g. because gas
+// estimation reverted.
+func (c *contractHelper) recordFailedDryRun(opts *bind.TransactOpts, contract *common.Address, input []byte, err error) error {
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	err = callError(err)
+	c.recordDryRun(&DryRunReport{
+		From:  opts.From,
+		To:    contract,
+		Value: value,
+		Data:  input,
+		Err:   err,
+	})
+
+	return err
+}
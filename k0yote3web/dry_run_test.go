@@ -0,0 +1,55 @@
+package k0yote3web
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunTransfer(t *testing.T) {
+	stub := &stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000), l1Fee: big.NewInt(0)}
+	wallet, helper := newStubWallet(t, stub)
+	helper.SetDryRun(true)
+	opts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		tx, err := wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+		assert.NoError(t, err)
+		// the nonce is given back after every dry run
+		assert.Equal(t, uint64(7), tx.Nonce())
+	}
+	assert.Empty(t, stub.sent)
+
+	reports := helper.DryRunReports()
+	assert.Len(t, reports, 2)
+	assert.NoError(t, reports[0].Err)
+	assert.Equal(t, uint64(21_000), reports[0].Gas)
+	// base fee 100 + tip 2, capped by the fee cap 202
+	assert.Equal(t, big.NewInt(102), reports[0].GasPrice)
+	assert.Equal(t, big.NewInt(21_000*102), reports[0].ExpectedCost)
+	assert.Equal(t, big.NewInt(21_000*202), reports[0].MaxCost)
+}
+
+func TestDryRunRevertReason(t *testing.T) {
+	stub := &stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000), l1Fee: big.NewInt(0)}
+	stub.revert = "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000b" +
+		"6e6f6e6578697374656e74000000000000000000000000000000000000000000"
+	wallet, helper := newStubWallet(t, stub)
+	helper.SetDryRun(true)
+	opts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	_, err = wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+	assert.EqualError(t, err, "execution reverted: nonexistent")
+
+	reports := helper.DryRunReports()
+	assert.Len(t, reports, 1)
+	assert.EqualError(t, reports[0].Err, "execution reverted: nonexistent")
+	assert.Contains(t, reports[0].String(), "would fail: [execution reverted: nonexistent]")
+}
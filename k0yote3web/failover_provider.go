@@ -0,0 +1,454 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	defaultMaxBlockLag         = 5
+	defaultHealthCheckInterval = 30 * time.Second
+	healthCheckTimeout         = 10 * time.Second
+)
+
+var errNoHealthyEndpoint = errors.New("no healthy RPC endpoint")
+
+type FailoverOptions struct {
+	// ChainID every endpoint must serve, the chain of the first responding endpoint by default
+	ChainID *big.Int
+	// MaxBlockLag is how many blocks an endpoint may be behind the most recent one and stay healthy
+	MaxBlockLag uint64
+	// HealthCheckInterval is how often the endpoints are checked in the background
+	HealthCheckInterval time.Duration
+	// Headers are sent with every request to every endpoint
+	Headers http.Header
+}
+
+// EndpointStatus is the outcome of the last health check of an endpoint.
+type EndpointStatus struct {
+	URL         string
+	Healthy     bool
+	BlockNumber uint64
+	Err         error
+}
+
+type rpcEndpoint struct {
+	url    string
+	client *ethclient.Client
+	status EndpointStatus
+}
+
+// FailoverProvider spreads requests over several RPC endpoints of the same chain.
+// Reads are sent round-robin to the healthy endpoints, while transactions, pending
+// state and subscriptions go to the first healthy one so they stay consistent. A
+// request failing because an endpoint is unreachable is retried on the next one.
+type FailoverProvider struct {
+	endpoints []*rpcEndpoint
+	opts      FailoverOptions
+	next      atomic.Uint32
+
+	mu      sync.RWMutex
+	chainID *big.Int
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ Provider = (*FailoverProvider)(nil)
+
+// NewFailoverProvider dials urls and checks their health, failing when none is healthy.
+func NewFailoverProvider(ctx context.Context, urls []string, opts *FailoverOptions) (*FailoverProvider, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("at least one RPC url is required")
+	}
+
+	p := &FailoverProvider{
+		opts: FailoverOptions{
+			MaxBlockLag:         defaultMaxBlockLag,
+			HealthCheckInterval: defaultHealthCheckInterval,
+		},
+		done: make(chan struct{}),
+	}
+	if opts != nil {
+		if opts.ChainID != nil {
+			p.opts.ChainID = opts.ChainID
+			p.chainID = opts.ChainID
+		}
+		if opts.MaxBlockLag > 0 {
+			p.opts.MaxBlockLag = opts.MaxBlockLag
+		}
+		if opts.HealthCheckInterval > 0 {
+			p.opts.HealthCheckInterval = opts.HealthCheckInterval
+		}
+		p.opts.Headers = opts.Headers
+	}
+
+	for _, url := range urls {
+		client, err := rpc.DialOptions(ctx, url, rpc.WithHeaders(p.opts.Headers))
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("cannot dial %s: %w", url, err)
+		}
+		p.endpoints = append(p.endpoints, &rpcEndpoint{
+			url:    url,
+			client: ethclient.NewClient(client),
+			status: EndpointStatus{URL: url},
+		})
+	}
+
+	if err := p.CheckHealth(ctx); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	go p.healthCheckLoop()
+
+	return p, nil
+}
+
+// CheckHealth checks every endpoint serves the expected chain and is at most MaxBlockLag
+// blocks behind the most recent of them.
+func (p *FailoverProvider) CheckHealth(ctx context.Context) error {
+	type check struct {
+		chainID *big.Int
+		head    uint64
+		err     error
+	}
+
+	checks := make([]check, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, client *ethclient.Client) {
+			defer wg.Done()
+			chainID, err := client.ChainID(ctx)
+			if err != nil {
+				checks[i].err = err
+				return
+			}
+			checks[i].chainID = chainID
+			checks[i].head, checks[i].err = client.BlockNumber(ctx)
+		}(i, e.client)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.chainID == nil {
+		for _, c := range checks {
+			if c.err == nil {
+				p.chainID = c.chainID
+				break
+			}
+		}
+	}
+
+	var highest uint64
+	for i := range checks {
+		if checks[i].err == nil && checks[i].chainID.Cmp(p.chainID) != 0 {
+			checks[i].err = fmt.Errorf("chain ID %v does not match %v", checks[i].chainID, p.chainID)
+		}
+		if checks[i].err == nil && checks[i].head > highest {
+			highest = checks[i].head
+		}
+	}
+
+	var firstErr error
+	healthy := 0
+	for i, e := range p.endpoints {
+		err := checks[i].err
+		if err == nil && highest-checks[i].head > p.opts.MaxBlockLag {
+			err = fmt.Errorf("%d blocks behind", highest-checks[i].head)
+		}
+		e.status = EndpointStatus{
+			URL:         e.url,
+			Healthy:     err == nil,
+			BlockNumber: checks[i].head,
+			Err:         err,
+		}
+		if err == nil {
+			healthy++
+		} else if firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", e.url, err)
+		}
+	}
+
+	if healthy == 0 {
+		return fmt.Errorf("%w: %v", errNoHealthyEndpoint, firstErr)
+	}
+
+	return nil
+}
+
+// Status returns the outcome of the last health check of every endpoint.
+func (p *FailoverProvider) Status() []EndpointStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	status := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		status = append(status, e.status)
+	}
+
+	return status
+}
+
+// Close stops the health checks and closes the connections to all endpoints.
+func (p *FailoverProvider) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		for _, e := range p.endpoints {
+			e.client.Close()
+		}
+	})
+}
+
+func (p *FailoverProvider) healthCheckLoop() {
+	ticker := time.NewTicker(p.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			_ = p.CheckHealth(ctx)
+			cancel()
+		}
+	}
+}
+
+// ordered returns the healthy endpoints, rotated when roundRobin is set, followed by the
+// unhealthy ones as a last resort.
+func (p *FailoverProvider) ordered(roundRobin bool) []*rpcEndpoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	healthy := make([]*rpcEndpoint, 0, len(p.endpoints))
+	unhealthy := make([]*rpcEndpoint, 0)
+	for _, e := range p.endpoints {
+		if e.status.Healthy {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+
+	if roundRobin && len(healthy) > 1 {
+		start := int(p.next.Add(1)-1) % len(healthy)
+		healthy = append(healthy[start:], healthy[:start]...)
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (p *FailoverProvider) markUnhealthy(e *rpcEndpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.status.Healthy = false
+	e.status.Err = err
+}
+
+// failover calls call on the endpoints in order until one of them answers.
+func failover[T any](p *FailoverProvider, roundRobin bool, call func(*ethclient.Client) (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	for _, e := range p.ordered(roundRobin) {
+		result, err = call(e.client)
+		if !isEndpointError(err) {
+			return result, err
+		}
+		p.markUnhealthy(e, err)
+	}
+
+	return result, err
+}
+
+// isEndpointError reports whether err means the endpoint could not serve the request, as
+// opposed to the node answering with an error such as a revert, which another endpoint
+// would answer the same way.
+func isEndpointError(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+func (p *FailoverProvider) subscribe(call func(*ethclient.Client) (ethereum.Subscription, error)) (ethereum.Subscription, error) {
+	var err error
+	for _, e := range p.ordered(false) {
+		var sub ethereum.Subscription
+		if sub, err = call(e.client); err == nil {
+			return sub, nil
+		}
+		// HTTP endpoints are healthy but cannot push notifications
+		if !errors.Is(err, rpc.ErrNotificationsUnsupported) && isEndpointError(err) {
+			p.markUnhealthy(e, err)
+		}
+	}
+
+	return nil, err
+}
+
+// Client returns the RPC client of the next healthy endpoint.
+func (p *FailoverProvider) Client() *rpc.Client {
+	return p.ordered(true)[0].client.Client()
+}
+
+func (p *FailoverProvider) ChainID(ctx context.Context) (*big.Int, error) {
+	return failover(p, true, func(c *ethclient.Client) (*big.Int, error) {
+		return c.ChainID(ctx)
+	})
+}
+
+func (p *FailoverProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	return failover(p, true, func(c *ethclient.Client) (uint64, error) {
+		return c.BlockNumber(ctx)
+	})
+}
+
+func (p *FailoverProvider) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return failover(p, true, func(c *ethclient.Client) (*types.Header, error) {
+		return c.HeaderByNumber(ctx, number)
+	})
+}
+
+func (p *FailoverProvider) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return p.subscribe(func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeNewHead(ctx, ch)
+	})
+}
+
+func (p *FailoverProvider) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return failover(p, true, func(c *ethclient.Client) (*big.Int, error) {
+		return c.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *FailoverProvider) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return failover(p, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.StorageAt(ctx, account, key, blockNumber)
+	})
+}
+
+func (p *FailoverProvider) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return failover(p, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.CodeAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *FailoverProvider) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return failover(p, true, func(c *ethclient.Client) (uint64, error) {
+		return c.NonceAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *FailoverProvider) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return failover(p, false, func(c *ethclient.Client) ([]byte, error) {
+		return c.PendingCodeAt(ctx, account)
+	})
+}
+
+func (p *FailoverProvider) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return failover(p, false, func(c *ethclient.Client) (uint64, error) {
+		return c.PendingNonceAt(ctx, account)
+	})
+}
+
+func (p *FailoverProvider) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return failover(p, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (p *FailoverProvider) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	return failover(p, false, func(c *ethclient.Client) ([]byte, error) {
+		return c.PendingCallContract(ctx, call)
+	})
+}
+
+func (p *FailoverProvider) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return failover(p, false, func(c *ethclient.Client) (uint64, error) {
+		return c.EstimateGas(ctx, call)
+	})
+}
+
+func (p *FailoverProvider) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return failover(p, true, func(c *ethclient.Client) (*big.Int, error) {
+		return c.SuggestGasPrice(ctx)
+	})
+}
+
+func (p *FailoverProvider) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return failover(p, true, func(c *ethclient.Client) (*big.Int, error) {
+		return c.SuggestGasTipCap(ctx)
+	})
+}
+
+func (p *FailoverProvider) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return failover(p, true, func(c *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+func (p *FailoverProvider) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return failover(p, true, func(c *ethclient.Client) ([]types.Log, error) {
+		return c.FilterLogs(ctx, q)
+	})
+}
+
+func (p *FailoverProvider) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return p.subscribe(func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
+
+func (p *FailoverProvider) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+
+	// Asked like SendTransaction so that a pending transaction is found where it was sent,
+	// the nonce manager takes a transaction another endpoint does not know yet as dropped
+	r, err := failover(p, false, func(c *ethclient.Client) (result, error) {
+		tx, isPending, err := c.TransactionByHash(ctx, hash)
+		return result{tx, isPending}, err
+	})
+
+	return r.tx, r.isPending, err
+}
+
+func (p *FailoverProvider) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return failover(p, true, func(c *ethclient.Client) (*types.Receipt, error) {
+		return c.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (p *FailoverProvider) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := failover(p, false, func(c *ethclient.Client) (struct{}, error) {
+		return struct{}{}, c.SendTransaction(ctx, tx)
+	})
+
+	return err
+}
@@ -0,0 +1,138 @@
+package k0yote3web
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubEndpoint struct {
+	chainID int64
+	head    uint64
+	// pending are the transactions in the pool of the endpoint
+	pending map[common.Hash]*types.Transaction
+}
+
+func (s *stubEndpoint) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(s.chainID))
+}
+
+func (s *stubEndpoint) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.head)
+}
+
+func (s *stubEndpoint) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	return s.pending[hash]
+}
+
+func newStubEndpoint(t *testing.T, stub *stubEndpoint) *httptest.Server {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", stub))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return httpServer
+}
+
+func TestFailoverProviderHealthCheck(t *testing.T) {
+	healthy := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 100})
+	lagging := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 90})
+	otherChain := newStubEndpoint(t, &stubEndpoint{chainID: 1, head: 100})
+
+	p, err := NewFailoverProvider(context.Background(), []string{healthy.URL, lagging.URL, otherChain.URL}, &FailoverOptions{ChainID: big.NewInt(137)})
+	assert.NoError(t, err)
+	defer p.Close()
+
+	status := p.Status()
+	assert.True(t, status[0].Healthy)
+	assert.False(t, status[1].Healthy)
+	assert.ErrorContains(t, status[1].Err, "10 blocks behind")
+	assert.False(t, status[2].Healthy)
+	assert.ErrorContains(t, status[2].Err, "chain ID 1 does not match 137")
+
+	_, err = NewFailoverProvider(context.Background(), []string{otherChain.URL}, &FailoverOptions{ChainID: big.NewInt(137)})
+	assert.ErrorIs(t, err, errNoHealthyEndpoint)
+}
+
+func TestFailoverProviderFailsOver(t *testing.T) {
+	first := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 100})
+	second := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 101})
+
+	p, err := NewFailoverProvider(context.Background(), []string{first.URL, second.URL}, nil)
+	assert.NoError(t, err)
+	defer p.Close()
+
+	// reads are spread over both endpoints
+	heads := map[uint64]bool{}
+	for i := 0; i < 2; i++ {
+		head, err := p.BlockNumber(context.Background())
+		assert.NoError(t, err)
+		heads[head] = true
+	}
+	assert.Len(t, heads, 2)
+
+	first.Close()
+	for i := 0; i < 3; i++ {
+		head, err := p.BlockNumber(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(101), head)
+	}
+	assert.False(t, p.Status()[0].Healthy)
+	assert.True(t, p.Status()[1].Healthy)
+}
+
+func TestFailoverProviderPendingTransaction(t *testing.T) {
+	key, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	assert.NoError(t, err)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(137)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(137), Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21_000,
+	})
+	assert.NoError(t, err)
+
+	// the transaction was sent to the first endpoint, the second one has not seen it yet
+	sentTo := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 100, pending: map[common.Hash]*types.Transaction{tx.Hash(): tx}})
+	lagging := newStubEndpoint(t, &stubEndpoint{chainID: 137, head: 100})
+
+	p, err := NewFailoverProvider(context.Background(), []string{sentTo.URL, lagging.URL}, nil)
+	assert.NoError(t, err)
+	defer p.Close()
+
+	for i := 0; i < 3; i++ {
+		got, isPending, err := p.TransactionByHash(context.Background(), tx.Hash())
+		if assert.NoError(t, err) {
+			assert.True(t, isPending)
+			assert.Equal(t, tx.Hash(), got.Hash())
+		}
+	}
+}
+
+func TestFailoverProviderHeaders(t *testing.T) {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", &stubEndpoint{chainID: 1, head: 1}))
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer jwt" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	_, err := NewFailoverProvider(context.Background(), []string{httpServer.URL}, nil)
+	assert.ErrorIs(t, err, errNoHealthyEndpoint)
+
+	p, err := NewFailoverProvider(context.Background(), []string{httpServer.URL}, &FailoverOptions{
+		Headers: http.Header{"Authorization": []string{"Bearer jwt"}},
+	})
+	assert.NoError(t, err)
+	p.Close()
+}
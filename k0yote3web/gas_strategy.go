@@ -0,0 +1,272 @@
+package k0yote3web
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+const defaultFeeHistoryBlocks = 20
+
+type GasSpeed string
+
+const (
+	GasSlow     GasSpeed = "slow"
+	GasStandard GasSpeed = "standard"
+	GasFast     GasSpeed = "fast"
+)
+
+// gasSpeeds are the tiers in the order of the reward percentiles requested from eth_feeHistory
+var gasSpeeds = []GasSpeed{GasSlow, GasStandard, GasFast}
+
+var rewardPercentiles = []float64{10, 50, 90}
+
+// legacyPriceBumps are the percent of the suggested gas price paid by each tier on chains without EIP-1559
+var legacyPriceBumps = map[GasSpeed]int64{GasSlow: 100, GasStandard: 110, GasFast: 125}
+
+type GasStrategyOptions struct {
+	// Speed is the tier used for transactions, standard by default
+	Speed GasSpeed
+	// BlockCount is the number of recent blocks the fee history is read for
+	BlockCount uint64
+	// MaxFeePerGas caps the fee (or gas price) of every transaction, no cap when nil
+	MaxFeePerGas *big.Int
+	// MaxPriorityFeePerGas caps the tip of every transaction, no cap when nil
+	MaxPriorityFeePerGas *big.Int
+}
+
+// GasFees are the fees of one tier. GasPrice is set on chains without EIP-1559, GasTipCap
+// and GasFeeCap otherwise.
+type GasFees struct {
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+type GasEstimate struct {
+	// BaseFee is the base fee of the next block, nil on chains without EIP-1559
+	BaseFee *big.Int
+	Fees    map[GasSpeed]GasFees
+}
+
+type OperationCost struct {
+	Gas uint64
+	// Err is why the gas of the operation could not be estimated, e.g. a revert
+	Err error
+}
+
+type BatchCost struct {
+	Operations []OperationCost
+	TotalGas   uint64
+	// ExpectedCost is the total at the next base fee plus the tip of each tier
+	ExpectedCost map[GasSpeed]*big.Int
+	// MaxCost is the total at the fee cap of each tier, the most the batch can cost
+	MaxCost map[GasSpeed]*big.Int
+}
+
+// GasStrategy prices transactions from the rewards paid in recent blocks.
+type GasStrategy struct {
+	handler *ProviderHandler
+	opts    GasStrategyOptions
+}
+
+func newGasStrategy(handler *ProviderHandler, opts *GasStrategyOptions) (*GasStrategy, error) {
+	o := GasStrategyOptions{
+		Speed:      GasStandard,
+		BlockCount: defaultFeeHistoryBlocks,
+	}
+	if opts != nil {
+		if opts.Speed != "" {
+			o.Speed = opts.Speed
+		}
+		if opts.BlockCount > 0 {
+			o.BlockCount = opts.BlockCount
+		}
+		o.MaxFeePerGas = opts.MaxFeePerGas
+		o.MaxPriorityFeePerGas = opts.MaxPriorityFeePerGas
+	}
+
+	if _, ok := legacyPriceBumps[o.Speed]; !ok {
+		return nil, fmt.Errorf("unknown gas speed: %s", o.Speed)
+	}
+
+	return &GasStrategy{
+		handler: handler,
+		opts:    o,
+	}, nil
+}
+
+// Estimate returns the fees of every tier. Tips are the median over the recent blocks of
+// the 10th, 50th and 90th percentile rewards, and fee caps leave room for the base fee to double.
+func (g *GasStrategy) Estimate(ctx context.Context) (*GasEstimate, error) {
+	ctx = ensureContext(ctx)
+	provider := g.handler.GetProvider()
+
+	head, err := provider.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil {
+		return g.legacyEstimate(ctx)
+	}
+
+	history, err := provider.FeeHistory(ctx, g.opts.BlockCount, nil, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, fmt.Errorf("empty fee history")
+	}
+	// The last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	estimate := &GasEstimate{
+		BaseFee: baseFee,
+		Fees:    make(map[GasSpeed]GasFees, len(gasSpeeds)),
+	}
+	for i, speed := range gasSpeeds {
+		tip := medianReward(history, i)
+		feeCap := new(big.Int).Add(tip, new(big.Int).Mul(baseFee, big.NewInt(basefeeWiggleMultiplier)))
+		estimate.Fees[speed] = g.capFees(GasFees{GasTipCap: tip, GasFeeCap: feeCap})
+	}
+
+	return estimate, nil
+}
+
+// Fees returns the fees of the configured speed.
+func (g *GasStrategy) Fees(ctx context.Context) (GasFees, error) {
+	estimate, err := g.Estimate(ctx)
+	if err != nil {
+		return GasFees{}, err
+	}
+
+	fees := estimate.Fees[g.opts.Speed]
+	if estimate.BaseFee != nil && fees.GasFeeCap.Cmp(estimate.BaseFee) < 0 {
+		return GasFees{}, fmt.Errorf("base fee %v is above the max fee per gas %v", estimate.BaseFee, fees.GasFeeCap)
+	}
+
+	return fees, nil
+}
+
+// Apply sets the fees of the configured speed on a copy of opts, keeping the fees already set.
+func (g *GasStrategy) Apply(opts *bind.TransactOpts) (*bind.TransactOpts, error) {
+	if opts.GasPrice != nil || opts.GasFeeCap != nil || opts.GasTipCap != nil {
+		return opts, nil
+	}
+
+	fees, err := g.Fees(opts.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := *opts
+	priced.GasPrice = fees.GasPrice
+	priced.GasTipCap = fees.GasTipCap
+	priced.GasFeeCap = fees.GasFeeCap
+
+	return &priced, nil
+}
+
+// SimulateBatch estimates the gas of every call and the total cost of sending them all.
+// Calls failing estimation are reported per operation and left out of the totals.
+func (g *GasStrategy) SimulateBatch(ctx context.Context, calls []ethereum.CallMsg) (*BatchCost, error) {
+	ctx = ensureContext(ctx)
+	estimate, err := g.Estimate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := &BatchCost{
+		Operations:   make([]OperationCost, 0, len(calls)),
+		ExpectedCost: make(map[GasSpeed]*big.Int, len(gasSpeeds)),
+		MaxCost:      make(map[GasSpeed]*big.Int, len(gasSpeeds)),
+	}
+	for _, call := range calls {
+		gas, err := g.handler.GetProvider().EstimateGas(ctx, call)
+		cost.Operations = append(cost.Operations, OperationCost{Gas: gas, Err: err})
+		if err == nil {
+			cost.TotalGas += gas
+		}
+	}
+
+	totalGas := new(big.Int).SetUint64(cost.TotalGas)
+	for _, speed := range gasSpeeds {
+		fees := estimate.Fees[speed]
+		if fees.GasPrice != nil {
+			cost.ExpectedCost[speed] = new(big.Int).Mul(totalGas, fees.GasPrice)
+			cost.MaxCost[speed] = cost.ExpectedCost[speed]
+			continue
+		}
+		price := bigMin(new(big.Int).Add(estimate.BaseFee, fees.GasTipCap), fees.GasFeeCap)
+		cost.ExpectedCost[speed] = new(big.Int).Mul(totalGas, price)
+		cost.MaxCost[speed] = new(big.Int).Mul(totalGas, fees.GasFeeCap)
+	}
+
+	return cost, nil
+}
+
+func (g *GasStrategy) legacyEstimate(ctx context.Context) (*GasEstimate, error) {
+	price, err := g.handler.GetProvider().SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &GasEstimate{Fees: make(map[GasSpeed]GasFees, len(gasSpeeds))}
+	for _, speed := range gasSpeeds {
+		gasPrice := new(big.Int).Mul(price, big.NewInt(legacyPriceBumps[speed]))
+		gasPrice.Div(gasPrice, big.NewInt(100))
+		if g.opts.MaxFeePerGas != nil {
+			gasPrice = bigMin(gasPrice, g.opts.MaxFeePerGas)
+		}
+		estimate.Fees[speed] = GasFees{GasPrice: gasPrice}
+	}
+
+	return estimate, nil
+}
+
+func (g *GasStrategy) capFees(fees GasFees) GasFees {
+	if g.opts.MaxPriorityFeePerGas != nil {
+		fees.GasTipCap = bigMin(fees.GasTipCap, g.opts.MaxPriorityFeePerGas)
+	}
+	if g.opts.MaxFeePerGas != nil {
+		fees.GasFeeCap = bigMin(fees.GasFeeCap, g.opts.MaxFeePerGas)
+	}
+	fees.GasTipCap = bigMin(fees.GasTipCap, fees.GasFeeCap)
+
+	return fees
+}
+
+// medianReward is the median of the reward at percentile index i over the blocks of history.
+// Empty blocks report a zero reward and are skipped unless every block is empty.
+func medianReward(history *ethereum.FeeHistory, i int) *big.Int {
+	rewards := make([]*big.Int, 0, len(history.Reward))
+	for block, reward := range history.Reward {
+		if i >= len(reward) {
+			continue
+		}
+		if block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0 {
+			continue
+		}
+		rewards = append(rewards, reward[i])
+	}
+	if len(rewards) == 0 {
+		return new(big.Int)
+	}
+
+	sort.Slice(rewards, func(a, b int) bool {
+		return rewards[a].Cmp(rewards[b]) < 0
+	})
+
+	return new(big.Int).Set(rewards[len(rewards)/2])
+}
+
+func bigMin(a, b *big.Int) *big.Int {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,97 @@
+package k0yote3web
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubFeeChain struct {
+	baseFee *big.Int
+}
+
+func (s *stubFeeChain) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{
+		Number:     big.NewInt(100),
+		Difficulty: new(big.Int),
+		BaseFee:    s.baseFee,
+	}
+}
+
+func (s *stubFeeChain) FeeHistory(count hexutil.Uint, last string, percentiles []float64) map[string]any {
+	return map[string]any{
+		"oldestBlock": "0x60",
+		"reward": [][]string{
+			{"0x1", "0xa", "0x64"},
+			{"0x0", "0x0", "0x0"},
+			{"0x3", "0x14", "0xc8"},
+			{"0x2", "0x1e", "0x12c"},
+		},
+		"baseFeePerGas": []string{"0x64", "0x64", "0x64", "0x64", "0x6e"},
+		"gasUsedRatio":  []float64{0.5, 0, 0.6, 0.4},
+	}
+}
+
+func (s *stubFeeChain) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1000))
+}
+
+func (s *stubFeeChain) EstimateGas(args map[string]any) hexutil.Uint64 {
+	return 50_000
+}
+
+func TestGasStrategyEstimate(t *testing.T) {
+	helper := newStubContractHelper(t, &stubFeeChain{baseFee: big.NewInt(100)})
+	strategy, err := newGasStrategy(helper.ProviderHandler, nil)
+	assert.NoError(t, err)
+
+	estimate, err := strategy.Estimate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(110), estimate.BaseFee)
+	// the empty block is skipped, medians of 1,3,2 / 10,20,30 / 100,200,300
+	assert.Equal(t, big.NewInt(2), estimate.Fees[GasSlow].GasTipCap)
+	assert.Equal(t, big.NewInt(222), estimate.Fees[GasSlow].GasFeeCap)
+	assert.Equal(t, big.NewInt(20), estimate.Fees[GasStandard].GasTipCap)
+	assert.Equal(t, big.NewInt(200), estimate.Fees[GasFast].GasTipCap)
+	assert.Equal(t, big.NewInt(420), estimate.Fees[GasFast].GasFeeCap)
+}
+
+func TestGasStrategyCeilings(t *testing.T) {
+	helper := newStubContractHelper(t, &stubFeeChain{baseFee: big.NewInt(100)})
+	strategy, err := newGasStrategy(helper.ProviderHandler, &GasStrategyOptions{
+		Speed:                GasFast,
+		MaxFeePerGas:         big.NewInt(300),
+		MaxPriorityFeePerGas: big.NewInt(50),
+	})
+	assert.NoError(t, err)
+
+	fees, err := strategy.Fees(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(50), fees.GasTipCap)
+	assert.Equal(t, big.NewInt(300), fees.GasFeeCap)
+
+	strategy.opts.MaxFeePerGas = big.NewInt(100)
+	_, err = strategy.Fees(context.Background())
+	assert.ErrorContains(t, err, "base fee 110 is above the max fee per gas 100")
+
+	_, err = newGasStrategy(helper.ProviderHandler, &GasStrategyOptions{Speed: "instant"})
+	assert.Error(t, err)
+}
+
+func TestGasStrategyLegacyAndBatch(t *testing.T) {
+	helper := newStubContractHelper(t, &stubFeeChain{})
+	strategy, err := newGasStrategy(helper.ProviderHandler, nil)
+	assert.NoError(t, err)
+
+	cost, err := strategy.SimulateBatch(context.Background(), []ethereum.CallMsg{{}, {}})
+	assert.NoError(t, err)
+	assert.Len(t, cost.Operations, 2)
+	assert.Equal(t, uint64(100_000), cost.TotalGas)
+	assert.Equal(t, big.NewInt(100_000*1000), cost.ExpectedCost[GasSlow])
+	assert.Equal(t, big.NewInt(100_000*1250), cost.MaxCost[GasFast])
+}
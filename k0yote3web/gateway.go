@@ -0,0 +1,134 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+// cidPlaceholder marks a subdomain gateway, e.g. https://{cid}.ipfs.dweb.link/
+const cidPlaceholder = "{cid}"
+
+var (
+	defaultIpfsGateways = []string{
+		publicIpfsGatewayUrl,
+		"https://{cid}.ipfs.dweb.link/",
+		"https://gateway.pinata.cloud/ipfs/",
+	}
+	defaultArweaveGateways = []string{
+		"https://arweave.net/",
+		"https://ar-io.net/",
+	}
+)
+
+// uriResolver downloads ipfs:// and ar:// uris through gateways, trying them in order until
+// one succeeds. Other uris are downloaded as they are. ipfs:// uris are read from the node
+// first when there is one.
+type uriResolver struct {
+	ipfsGateways    []string
+	arweaveGateways []string
+	node            *ipfsFetcher
+}
+
+func newURIResolver(ipfsGateways, arweaveGateways []string) *uriResolver {
+	r := &uriResolver{
+		ipfsGateways:    defaultIpfsGateways,
+		arweaveGateways: defaultArweaveGateways,
+	}
+	if len(ipfsGateways) > 0 {
+		r.ipfsGateways = ipfsGateways
+	}
+	if len(arweaveGateways) > 0 {
+		r.arweaveGateways = arweaveGateways
+	}
+
+	return r
+}
+
+func (r *uriResolver) download(uri string) ([]byte, error) {
+	if r.node != nil && strings.HasPrefix(uri, "ipfs://") {
+		c, p, ok := parseIpfsURI(uri)
+		if !ok {
+			return nil, fmt.Errorf("invalid ipfs uri: %s", uri)
+		}
+
+		b, err := r.node.fetch(context.Background(), c, p)
+		if err == nil {
+			return b, nil
+		}
+		log.Printf("ipfs node failed, falling back to gateways: %s: %v\n", uri, err)
+	}
+
+	urls, err := r.resolve(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, u := range urls {
+		b, err := downloadFile(u)
+		if err == nil {
+			return b, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", u, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// resolve returns the urls uri can be downloaded from, in the order of the gateways.
+func (r *uriResolver) resolve(uri string) ([]string, error) {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		c, p, ok := parseIpfsURI(uri)
+		if !ok {
+			return nil, fmt.Errorf("invalid ipfs uri: %s", uri)
+		}
+
+		urls := make([]string, 0, len(r.ipfsGateways))
+		for _, gateway := range r.ipfsGateways {
+			u, err := ipfsGatewayURL(gateway, c, p)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, u)
+		}
+		return urls, nil
+	case strings.HasPrefix(uri, "ar://"):
+		p := strings.TrimPrefix(uri, "ar://")
+		if p == "" {
+			return nil, fmt.Errorf("invalid arweave uri: %s", uri)
+		}
+
+		urls := make([]string, 0, len(r.arweaveGateways))
+		for _, gateway := range r.arweaveGateways {
+			u, err := url.JoinPath(gateway, p)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, u)
+		}
+		return urls, nil
+	}
+
+	return []string{uri}, nil
+}
+
+// ipfsGatewayURL is the url of the path p below c on a path gateway (https://ipfs.io/ipfs/) or
+// on a subdomain gateway. Subdomains are case-insensitive so they take the base32 CIDv1.
+func ipfsGatewayURL(gateway string, c cid.Cid, p string) (string, error) {
+	if !strings.Contains(gateway, cidPlaceholder) {
+		return url.JoinPath(gateway, c.String(), p)
+	}
+
+	if c.Version() == 0 {
+		c = cid.NewCidV1(cid.DagProtobuf, c.Hash())
+	}
+
+	return url.JoinPath(strings.Replace(gateway, cidPlaceholder, c.String(), 1), p)
+}
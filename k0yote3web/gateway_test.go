@@ -0,0 +1,60 @@
+package k0yote3web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURIResolverResolve(t *testing.T) {
+	resolver := newURIResolver(nil, nil)
+
+	urls, err := resolver.resolve("ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://ipfs.io/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png",
+		"https://bafybeie5nqv6kd3qnfjupgvz34woh3oksc3iau6abmyajn7qvtf6d2ho34.ipfs.dweb.link/1.png",
+		"https://gateway.pinata.cloud/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png",
+	}, urls)
+
+	urls, err = resolver.resolve("ar://bNbA3TEQVL60xlgCcqdz4ZPHFZ711cZ3hmkpGttDt_U/1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://arweave.net/bNbA3TEQVL60xlgCcqdz4ZPHFZ711cZ3hmkpGttDt_U/1.json", urls[0])
+
+	urls, err = resolver.resolve("https://example.com/1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/1.json"}, urls)
+
+	_, err = resolver.resolve("ipfs://not-a-cid/1.json")
+	assert.Error(t, err)
+}
+
+func TestURIResolverFallback(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer down.Close()
+
+	var requested string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(`{"name":"Foo #1"}`))
+	}))
+	defer up.Close()
+
+	resolver := newURIResolver([]string{down.URL + "/ipfs/", up.URL + "/ipfs/"}, nil)
+	b, err := resolver.download("ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"Foo #1"}`, string(b))
+	assert.Equal(t, "/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1", requested)
+
+	resolver = newURIResolver([]string{down.URL + "/ipfs/"}, nil)
+	_, err = resolver.download("ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1")
+	assert.ErrorContains(t, err, "429")
+
+	filename, err := getFilename("ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG")
+	assert.NoError(t, err)
+	assert.Equal(t, "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", filename)
+}
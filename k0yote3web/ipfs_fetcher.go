@@ -0,0 +1,80 @@
+package k0yote3web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/ipfs/go-cid"
+	ipfsapi "github.com/ipfs/kubo/client/rpc"
+
+	caopts "github.com/ipfs/boxo/coreiface/options"
+	ipfsPath "github.com/ipfs/boxo/coreiface/path"
+	ipfsFiles "github.com/ipfs/boxo/files"
+)
+
+// ipfsFetcher reads ipfs content from a kubo node, which fetches it from the network when it
+// does not have it yet, instead of going through public gateways.
+type ipfsFetcher struct {
+	opts   *IPFSOptions
+	client *ipfsapi.HttpApi
+}
+
+func newIpfsFetcher(opts *IPFSOptions) (*ipfsFetcher, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("provider type is required")
+	}
+
+	o := *opts
+	if len(o.ApiURL) == 0 {
+		o.ApiURL = defaultIpfsAPI
+		if o.ProviderType == IPFS_INFURA {
+			o.ApiURL = infuraAPI
+		}
+	}
+
+	client, err := newIpfsClient(&o, &http.Client{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ipfsFetcher{
+		opts:   &o,
+		client: client,
+	}, nil
+}
+
+// fetch returns the file at path p below c, pinning it on the node when opts.Pin is set so
+// that it outlives the garbage collection of the node. A failed pin is only logged, many hosted
+// nodes do not allow it.
+func (f *ipfsFetcher) fetch(ctx context.Context, c cid.Cid, p string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	filePath := ipfsPath.Join(ipfsPath.IpfsPath(c), p)
+	node, err := f.client.Unixfs().Get(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer node.Close()
+
+	file := ipfsFiles.ToFile(node)
+	if file == nil {
+		return nil, fmt.Errorf("%s is a directory", filePath)
+	}
+
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.opts.Pin {
+		if err := f.client.Pin().Add(ctx, filePath, caopts.Pin.Recursive(true)); err != nil {
+			log.Printf("failed to pin %s: %v\n", filePath, err)
+		}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,87 @@
+package k0yote3web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fetchedURI = "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1"
+
+// newStubKubo serves the kubo RPC calls of a file fetch, failing them all when down. Pins are
+// rejected without pinned.
+func newStubKubo(t *testing.T, down bool, pinned *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"Message":"context deadline exceeded","Code":0,"Type":"error"}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v0/files/stat":
+			w.Write([]byte(`{"Hash":"QmNode","Type":"file","Size":17}`))
+		case "/api/v0/cat":
+			w.Write([]byte(`{"name":"Foo #1"}`))
+		case "/api/v0/pin/add":
+			if pinned == nil {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"Message":"pin/add is not allowed","Code":0,"Type":"error"}`))
+				return
+			}
+			*pinned = append(*pinned, r.URL.Query().Get("arg"))
+			w.Write([]byte(`{"Pins":["QmNode"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestURIResolverIpfsNode(t *testing.T) {
+	var pinned []string
+	node, err := newIpfsFetcher(&IPFSOptions{ProviderType: IPFS_LOCAL, ApiURL: newStubKubo(t, false, &pinned).URL, Pin: true})
+	assert.NoError(t, err)
+
+	resolver := newURIResolver([]string{"http://127.0.0.1:1/ipfs/"}, nil)
+	resolver.node = node
+
+	b, err := resolver.download(fetchedURI)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"Foo #1"}`, string(b))
+	assert.Equal(t, []string{"/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1"}, pinned)
+}
+
+func TestURIResolverIpfsNodeFallback(t *testing.T) {
+	node, err := newIpfsFetcher(&IPFSOptions{ProviderType: IPFS_LOCAL, ApiURL: newStubKubo(t, true, nil).URL})
+	assert.NoError(t, err)
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Foo #1"}`))
+	}))
+	defer gateway.Close()
+
+	resolver := newURIResolver([]string{gateway.URL + "/ipfs/"}, nil)
+	resolver.node = node
+
+	b, err := resolver.download(fetchedURI)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"Foo #1"}`, string(b))
+}
+
+func TestURIResolverIpfsNodePinRejected(t *testing.T) {
+	node, err := newIpfsFetcher(&IPFSOptions{ProviderType: IPFS_LOCAL, ApiURL: newStubKubo(t, false, nil).URL, Pin: true})
+	assert.NoError(t, err)
+
+	// the content read from the node is kept, no gateway is tried
+	resolver := newURIResolver([]string{"http://127.0.0.1:1/ipfs/"}, nil)
+	resolver.node = node
+
+	b, err := resolver.download(fetchedURI)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"Foo #1"}`, string(b))
+}
@@ -56,15 +56,11 @@ func (h *IpfsUploader) upload(path string) (cid.Cid, error) {
 		httpClient = &http.Client{}
 	)
 
-	client, err := ipfsapi.NewURLApiWithClient(h.opts.ApiURL, httpClient)
+	client, err := newIpfsClient(h.opts, httpClient)
 	if err != nil {
 		return cid, err
 	}
 
-	if h.opts != nil && h.opts.ProviderType != IPFS_LOCAL {
-		h.basicAuth(client)
-	}
-
 	stat, err := os.Lstat(path)
 	if err != nil {
 		return cid, err
@@ -126,9 +122,19 @@ func (h *IpfsUploader) upload(path string) (cid.Cid, error) {
 	return res.Cid(), nil
 }
 
-func (h *IpfsUploader) basicAuth(client *ipfsapi.HttpApi) {
-	basicAuth := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(h.opts.ProjectID+":"+h.opts.Secret)))
-	client.Headers.Add("Authorization", basicAuth)
+// newIpfsClient returns a kubo RPC client of opts.ApiURL, authenticated unless the node is local.
+func newIpfsClient(opts *IPFSOptions, httpClient *http.Client) (*ipfsapi.HttpApi, error) {
+	client, err := ipfsapi.NewURLApiWithClient(opts.ApiURL, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ProviderType != IPFS_LOCAL {
+		basicAuth := fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(opts.ProjectID+":"+opts.Secret)))
+		client.Headers.Add("Authorization", basicAuth)
+	}
+
+	return client, nil
 }
 
 func elapse(start time.Time) {
@@ -0,0 +1,44 @@
+package k0yote3web
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+// parseIpfsURI returns the CID and the path below it of an ipfs:// uri or of a path
+// (/ipfs/<cid>/...) or subdomain (<cid>.ipfs.<host>) gateway url.
+func parseIpfsURI(uri string) (cid.Cid, string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return cid.Undef, "", false
+	}
+
+	var root, rest string
+	switch {
+	case u.Scheme == "ipfs":
+		// ipfs://ipfs/<cid> is a common mistake that clients still resolve
+		p := strings.TrimPrefix(u.Host+u.Path, "ipfs/")
+		root, rest, _ = strings.Cut(p, "/")
+	case u.Scheme == "http" || u.Scheme == "https":
+		if label, host, ok := strings.Cut(u.Host, ".ipfs."); ok && host != "" {
+			root, rest = label, strings.TrimPrefix(u.Path, "/")
+			break
+		}
+		_, p, ok := strings.Cut(u.Path, "/ipfs/")
+		if !ok {
+			return cid.Undef, "", false
+		}
+		root, rest, _ = strings.Cut(p, "/")
+	default:
+		return cid.Undef, "", false
+	}
+
+	c, err := cid.Decode(root)
+	if err != nil {
+		return cid.Undef, "", false
+	}
+
+	return c, rest, true
+}
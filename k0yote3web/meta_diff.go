@@ -0,0 +1,244 @@
+package k0yote3web
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type DiffKind string
+
+const (
+	DiffAdded   DiffKind = "added"
+	DiffRemoved DiffKind = "removed"
+	DiffChanged DiffKind = "changed"
+)
+
+// FieldDiff is a change of a top level field, or of an attribute when Field is attributes[<trait_type>].
+type FieldDiff struct {
+	Field  string   `json:"field"`
+	Kind   DiffKind `json:"kind"`
+	Before any      `json:"before,omitempty"`
+	After  any      `json:"after,omitempty"`
+}
+
+func (d FieldDiff) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %s", d.Field, jsonString(d.After))
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %s", d.Field, jsonString(d.Before))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", d.Field, jsonString(d.Before), jsonString(d.After))
+}
+
+// TokenDiff are the changes of the metadata file of a token. Kind is added or removed when
+// the file is only in one of the snapshots, Fields is empty then.
+type TokenDiff struct {
+	File   string      `json:"file"`
+	Kind   DiffKind    `json:"kind"`
+	Fields []FieldDiff `json:"fields,omitempty"`
+}
+
+func (d *TokenDiff) String() string {
+	if d.Kind != DiffChanged {
+		return fmt.Sprintf("%s: %s", d.File, d.Kind)
+	}
+
+	lines := make([]string, 0, len(d.Fields)+1)
+	lines = append(lines, d.File+":")
+	for _, field := range d.Fields {
+		lines = append(lines, "  "+field.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MetaDiffer compares two folders of metadata, e.g. the origin and the rewritten metadata or
+// two crawls of a collection.
+type MetaDiffer struct {
+	beforeDir string
+	afterDir  string
+}
+
+func newMetaDiffer(beforeDir, afterDir string) (*MetaDiffer, error) {
+	before := metadataFolderName
+	after := uploadFolderName
+
+	if len(beforeDir) > 0 {
+		before = beforeDir
+	}
+	if len(afterDir) > 0 {
+		after = afterDir
+	}
+
+	for _, dir := range []string{before, after} {
+		if _, err := getSavePath(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MetaDiffer{
+		beforeDir: before,
+		afterDir:  after,
+	}, nil
+}
+
+// Diff compares the files of the same name and returns the tokens that differ, in file name order.
+func (d *MetaDiffer) Diff() ([]*TokenDiff, error) {
+	before, err := readMetaFields(d.beforeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := readMetaFields(d.afterDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(before)+len(after))
+	for file := range before {
+		files = append(files, file)
+	}
+	for file := range after {
+		if _, ok := before[file]; !ok {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	diffs := []*TokenDiff{}
+	for _, file := range files {
+		b, hasBefore := before[file]
+		a, hasAfter := after[file]
+		switch {
+		case !hasBefore:
+			diffs = append(diffs, &TokenDiff{File: file, Kind: DiffAdded})
+		case !hasAfter:
+			diffs = append(diffs, &TokenDiff{File: file, Kind: DiffRemoved})
+		default:
+			if fields := DiffMeta(b, a); len(fields) > 0 {
+				diffs = append(diffs, &TokenDiff{File: file, Kind: DiffChanged, Fields: fields})
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// DiffMeta compares the fields of two metadata of a token, in field name order. Attributes are
+// compared by trait_type.
+func DiffMeta(before, after map[string]any) []FieldDiff {
+	before = flattenAttributes(before)
+	after = flattenAttributes(after)
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	diffs := []FieldDiff{}
+	for _, field := range fields {
+		b, hasBefore := before[field]
+		a, hasAfter := after[field]
+		switch {
+		case !hasBefore:
+			diffs = append(diffs, FieldDiff{Field: field, Kind: DiffAdded, After: a})
+		case !hasAfter:
+			diffs = append(diffs, FieldDiff{Field: field, Kind: DiffRemoved, Before: b})
+		case jsonString(b) != jsonString(a):
+			diffs = append(diffs, FieldDiff{Field: field, Kind: DiffChanged, Before: b, After: a})
+		}
+	}
+
+	return diffs
+}
+
+// flattenAttributes replaces the attributes array with a field per trait type, the attribute
+// without its trait_type. Repeated trait types are numbered from the second one.
+func flattenAttributes(meta map[string]any) map[string]any {
+	attributes, ok := meta["attributes"].([]any)
+	if !ok {
+		return meta
+	}
+
+	flat := make(map[string]any, len(meta)+len(attributes))
+	for field, value := range meta {
+		if field != "attributes" {
+			flat[field] = value
+		}
+	}
+
+	seen := make(map[string]int, len(attributes))
+	for i, a := range attributes {
+		attribute, ok := a.(map[string]any)
+		if !ok {
+			flat[fmt.Sprintf("attributes[%d]", i)] = a
+			continue
+		}
+
+		traitType := fmt.Sprint(attribute["trait_type"])
+		seen[traitType]++
+		if seen[traitType] > 1 {
+			traitType = fmt.Sprintf("%s#%d", traitType, seen[traitType])
+		}
+
+		value := make(map[string]any, len(attribute))
+		for field, v := range attribute {
+			if field != "trait_type" {
+				value[field] = v
+			}
+		}
+		flat["attributes["+traitType+"]"] = value
+	}
+
+	return flat
+}
+
+func readMetaFields(inputDir string) (map[string]map[string]any, error) {
+	metaDir, err := getSavePath(inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metaFiles, err := os.ReadDir(metaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make(map[string]map[string]any, len(metaFiles))
+	for _, metaFile := range metaFiles {
+		if metaFile.IsDir() {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(metaDir, metaFile.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		fields := map[string]any{}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, fmt.Errorf("%s: %w", metaFile.Name(), err)
+		}
+		metas[metaFile.Name()] = fields
+	}
+
+	return metas, nil
+}
+
+func jsonString(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
@@ -0,0 +1,71 @@
+package k0yote3web
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMeta(t *testing.T) {
+	before := map[string]any{
+		"name":  "Foo #1",
+		"image": "https://old.host/1.png",
+		"attributes": []any{
+			map[string]any{"trait_type": "Eyes", "value": "Blue"},
+			map[string]any{"trait_type": "Hat", "value": "Cap"},
+			map[string]any{"trait_type": "Level", "value": float64(1)},
+		},
+	}
+	after := map[string]any{
+		"name":         "Foo #1",
+		"image":        "ipfs://cid/1.png",
+		"external_url": "https://foo.xyz/1",
+		"attributes": []any{
+			map[string]any{"trait_type": "Eyes", "value": "Blue"},
+			map[string]any{"trait_type": "Level", "value": float64(2)},
+			map[string]any{"trait_type": "Mouth", "value": "Smile"},
+		},
+	}
+
+	assert.Equal(t, []FieldDiff{
+		{Field: "attributes[Hat]", Kind: DiffRemoved, Before: map[string]any{"value": "Cap"}},
+		{Field: "attributes[Level]", Kind: DiffChanged, Before: map[string]any{"value": float64(1)}, After: map[string]any{"value": float64(2)}},
+		{Field: "attributes[Mouth]", Kind: DiffAdded, After: map[string]any{"value": "Smile"}},
+		{Field: "external_url", Kind: DiffAdded, After: "https://foo.xyz/1"},
+		{Field: "image", Kind: DiffChanged, Before: "https://old.host/1.png", After: "ipfs://cid/1.png"},
+	}, DiffMeta(before, after))
+
+	diff := &TokenDiff{File: "1.json", Kind: DiffChanged, Fields: DiffMeta(before, after)[3:]}
+	assert.Equal(t, "1.json:\n"+
+		"  + external_url: \"https://foo.xyz/1\"\n"+
+		"  ~ image: \"https://old.host/1.png\" -> \"ipfs://cid/1.png\"", diff.String())
+
+	assert.Empty(t, DiffMeta(before, before))
+}
+
+func TestMetaDifferDiff(t *testing.T) {
+	dir := t.TempDir()
+	beforeDir, afterDir := filepath.Join(dir, "before"), filepath.Join(dir, "after")
+	for folder, files := range map[string]map[string]string{
+		beforeDir: {"1": `{"name":"Foo #1"}`, "2": `{"name":"Foo #2"}`, "3": `{"name":"Foo #3"}`},
+		afterDir:  {"1": `{"name":"Foo #1"}`, "2": `{"name":"Bar #2"}`, "4": `{"name":"Foo #4"}`},
+	} {
+		assert.NoError(t, os.Mkdir(folder, 0777))
+		for file, meta := range files {
+			assert.NoError(t, os.WriteFile(filepath.Join(folder, file), []byte(meta), 0644))
+		}
+	}
+
+	differ, err := newMetaDiffer(beforeDir, afterDir)
+	assert.NoError(t, err)
+
+	diffs, err := differ.Diff()
+	assert.NoError(t, err)
+	assert.Equal(t, []*TokenDiff{
+		{File: "2", Kind: DiffChanged, Fields: []FieldDiff{{Field: "name", Kind: DiffChanged, Before: "Foo #2", After: "Bar #2"}}},
+		{File: "3", Kind: DiffRemoved},
+		{File: "4", Kind: DiffAdded},
+	}, diffs)
+}
@@ -0,0 +1,219 @@
+package k0yote3web
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSONL ExportFormat = "jsonl"
+)
+
+// traitColumnPrefix keeps trait columns apart from the metadata fields, e.g. a trait named "name"
+const traitColumnPrefix = "trait_"
+
+type ExportOptions struct {
+	// InputDir is the folder of metadata, internal/meta by default
+	InputDir string
+	// ImageDir is where the images hashed with FileHash are, internal/image by default
+	ImageDir string
+	// Format is csv by default
+	Format ExportFormat
+	// ImageCID adds the image_cid column, the CID of ipfs image urls (of their folder for a file of a folder)
+	ImageCID bool
+	// FileHash adds the image_sha256 column, the hash of the downloaded image files
+	FileHash bool
+}
+
+// MetaExporter flattens the metadata of a collection to a row per token and a column per trait type.
+type MetaExporter struct {
+	opts ExportOptions
+}
+
+func newMetaExporter(opts *ExportOptions) (*MetaExporter, error) {
+	o := ExportOptions{
+		InputDir: metadataFolderName,
+		ImageDir: imageFolderName,
+		Format:   ExportCSV,
+	}
+	if opts != nil {
+		if len(opts.InputDir) > 0 {
+			o.InputDir = opts.InputDir
+		}
+		if len(opts.ImageDir) > 0 {
+			o.ImageDir = opts.ImageDir
+		}
+		if len(opts.Format) > 0 {
+			o.Format = opts.Format
+		}
+		o.ImageCID = opts.ImageCID
+		o.FileHash = opts.FileHash
+	}
+
+	if o.Format != ExportCSV && o.Format != ExportJSONL {
+		return nil, fmt.Errorf("unknown export format: %s", o.Format)
+	}
+
+	return &MetaExporter{
+		opts: o,
+	}, nil
+}
+
+// Export writes the rows to w and returns the number of tokens. Every JSONL line has every
+// column, null when the token has no such trait, so that it loads as a table.
+func (e *MetaExporter) Export(w io.Writer) (int, error) {
+	metas, err := ReadMetaDir(e.opts.InputDir)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.write(w, metas)
+}
+
+func (e *MetaExporter) write(w io.Writer, metas []*MetaDataFile) (int, error) {
+	sortMetaFiles(metas)
+
+	columns, rows, err := e.rows(metas)
+	if err != nil {
+		return 0, err
+	}
+
+	if e.opts.Format == ExportJSONL {
+		encoder := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return 0, err
+			}
+		}
+		return len(rows), nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return 0, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			if value := row[column]; value != nil {
+				record[i] = csvValue(value)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+
+	return len(rows), writer.Error()
+}
+
+func (e *MetaExporter) rows(metas []*MetaDataFile) ([]string, []map[string]any, error) {
+	columns := []string{"token", "name", "description", "image", "external_url", "animation_url"}
+	if e.opts.ImageCID {
+		columns = append(columns, "image_cid")
+	}
+	if e.opts.FileHash {
+		columns = append(columns, "image_sha256")
+	}
+
+	imageDir := ""
+	if e.opts.FileHash {
+		var err error
+		if imageDir, err = getSavePath(e.opts.ImageDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	traitColumns := map[string]bool{}
+	rows := make([]map[string]any, 0, len(metas))
+	for _, meta := range metas {
+		row := map[string]any{
+			"token":         tokenID(meta.File),
+			"name":          meta.Name,
+			"description":   meta.Description,
+			"image":         meta.Image,
+			"external_url":  meta.ExternalURL,
+			"animation_url": meta.AnimationURL,
+		}
+
+		if e.opts.ImageCID {
+			row["image_cid"] = nil
+			if c, _, ok := parseIpfsURI(meta.Image); ok {
+				row["image_cid"] = c.String()
+			}
+		}
+
+		if e.opts.FileHash {
+			filename, err := imageFilename(meta.Image)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", meta.File, err)
+			}
+			image, err := os.ReadFile(filepath.Join(imageDir, filename))
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: image of %s: %w", meta.File, meta.Image, err)
+			}
+			hash := sha256.Sum256(image)
+			row["image_sha256"] = hex.EncodeToString(hash[:])
+		}
+
+		// Repeated trait types are numbered from the second one, as in DiffMeta
+		seen := make(map[string]int, len(meta.Attributes))
+		for _, attribute := range meta.Attributes {
+			column := traitColumnPrefix + attribute.TraitType
+			seen[column]++
+			if seen[column] > 1 {
+				column = fmt.Sprintf("%s#%d", column, seen[column])
+			}
+			traitColumns[column] = true
+			row[column] = attribute.Value
+		}
+
+		rows = append(rows, row)
+	}
+
+	sortedTraits := make([]string, 0, len(traitColumns))
+	for column := range traitColumns {
+		sortedTraits = append(sortedTraits, column)
+	}
+	sort.Strings(sortedTraits)
+	columns = append(columns, sortedTraits...)
+
+	for _, row := range rows {
+		for _, column := range sortedTraits {
+			if _, ok := row[column]; !ok {
+				row[column] = nil
+			}
+		}
+	}
+
+	return columns, rows, nil
+}
+
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return jsonString(value)
+}
+
+// sortMetaFiles orders metas by token id, numerically when the names are numbers.
+func sortMetaFiles(metas []*MetaDataFile) {
+	sort.Slice(metas, func(i, j int) bool {
+		return tokenFileLess(metas[i].File, metas[j].File)
+	})
+}
@@ -0,0 +1,87 @@
+package k0yote3web
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportMetas() []*MetaDataFile {
+	return []*MetaDataFile{
+		{File: "10", MetaData: MetaData{Name: "Foo #10", Image: "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/10.png", Attributes: []Attribute{
+			{TraitType: "Level", Value: float64(2)},
+		}}},
+		{File: "2", MetaData: MetaData{Name: "Foo #2", Image: "https://example.com/2.png", Attributes: []Attribute{
+			{TraitType: "Eyes", Value: "Blue, light"},
+			{TraitType: "Level", Value: float64(1.5)},
+		}}},
+	}
+}
+
+func TestMetaExporterCSV(t *testing.T) {
+	exporter, err := newMetaExporter(&ExportOptions{ImageCID: true})
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	count, err := exporter.write(&b, exportMetas())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{
+		"token,name,description,image,external_url,animation_url,image_cid,trait_Eyes,trait_Level",
+		`2,Foo #2,,https://example.com/2.png,,,,"Blue, light",1.5`,
+		"10,Foo #10,,ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/10.png,,,QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG,,2",
+	}, strings.Split(strings.TrimSpace(b.String()), "\n"))
+
+	_, err = newMetaExporter(&ExportOptions{Format: "parquet"})
+	assert.Error(t, err)
+}
+
+func TestMetaExporterJSONL(t *testing.T) {
+	exporter, err := newMetaExporter(&ExportOptions{Format: ExportJSONL})
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	_, err = exporter.write(&b, exportMetas())
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.JSONEq(t, `{"token":"10","name":"Foo #10","description":"","image":"ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/10.png","external_url":"","animation_url":"","trait_Eyes":null,"trait_Level":2}`, lines[1])
+}
+
+func TestMetaExporterRepeatedTrait(t *testing.T) {
+	exporter, err := newMetaExporter(nil)
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	_, err = exporter.write(&b, []*MetaDataFile{
+		{File: "1", MetaData: MetaData{Name: "Foo #1", Attributes: []Attribute{
+			{TraitType: "Accessory", Value: "Hat"},
+			{TraitType: "Accessory", Value: "Scarf"},
+		}}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"token,name,description,image,external_url,animation_url,trait_Accessory,trait_Accessory#2",
+		"1,Foo #1,,,,,Hat,Scarf",
+	}, strings.Split(strings.TrimSpace(b.String()), "\n"))
+}
+
+func TestParseIpfsURI(t *testing.T) {
+	for _, uri := range []string{
+		"ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png",
+		"ipfs://ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png",
+		"https://ipfs.io/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png",
+		"https://bafybeie5nqv6kd3qnfjupgvz34woh3oksc3iau6abmyajn7qvtf6d2ho34.ipfs.dweb.link/1.png",
+	} {
+		c, p, ok := parseIpfsURI(uri)
+		assert.True(t, ok, uri)
+		assert.Equal(t, "1.png", p, uri)
+		assert.Equal(t, "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", c.Hash().B58String(), uri)
+	}
+
+	_, _, ok := parseIpfsURI("https://example.com/1.png")
+	assert.False(t, ok)
+}
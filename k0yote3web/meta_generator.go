@@ -0,0 +1,263 @@
+package k0yote3web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metaColumns are the CSV columns mapped to MetaData fields, the other columns are attributes
+var metaColumns = map[string]func(m *MetaData, value string){
+	"name":             func(m *MetaData, value string) { m.Name = value },
+	"description":      func(m *MetaData, value string) { m.Description = value },
+	"image":            func(m *MetaData, value string) { m.Image = value },
+	"external_url":     func(m *MetaData, value string) { m.ExternalURL = value },
+	"animation_url":    func(m *MetaData, value string) { m.AnimationURL = value },
+	"youtube_url":      func(m *MetaData, value string) { m.YoutubeURL = value },
+	"background_color": func(m *MetaData, value string) { m.BackgroundColor = value },
+}
+
+// dateLayouts are the layouts accepted for date attributes, besides unix timestamps
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "2006/01/02"}
+
+type GenerateMetaOptions struct {
+	// CSVPath is the CSV file with a header row and a row per token
+	CSVPath string
+	// ImageDir is the folder of the images named in the image column, internal/image by default
+	ImageDir string
+	// OutputDir is where the metadata is written, internal/meta by default
+	OutputDir string
+	// ImageBaseURL prefixes the image file names, they are kept as they are when empty and
+	// replaced by the rewriter later
+	ImageBaseURL string
+	// StartTokenID is the token id of the first row when there is no id column, 1 by default
+	StartTokenID int
+}
+
+// MetaGenerator writes the metadata of a collection from a CSV file. The id (or token_id)
+// column, a non-negative integer, names the files, the columns named after MetaData fields set them, and every other
+// column is an attribute named after the column. A column named "Trait:type" is a numeric
+// attribute of the OpenSea display type (number, boost_number, boost_percentage or date).
+type MetaGenerator struct {
+	opts GenerateMetaOptions
+}
+
+type metaColumn struct {
+	index       int
+	field       func(m *MetaData, value string)
+	traitType   string
+	displayType string
+}
+
+func newMetaGenerator(opts *GenerateMetaOptions) (*MetaGenerator, error) {
+	o := GenerateMetaOptions{
+		ImageDir:     imageFolderName,
+		OutputDir:    metadataFolderName,
+		StartTokenID: 1,
+	}
+	if opts != nil {
+		o.CSVPath = opts.CSVPath
+		o.ImageBaseURL = opts.ImageBaseURL
+		if len(opts.ImageDir) > 0 {
+			o.ImageDir = opts.ImageDir
+		}
+		if len(opts.OutputDir) > 0 {
+			o.OutputDir = opts.OutputDir
+		}
+		if opts.StartTokenID > 0 {
+			o.StartTokenID = opts.StartTokenID
+		}
+	}
+
+	if len(o.CSVPath) == 0 {
+		return nil, fmt.Errorf("csv path is required")
+	}
+
+	return &MetaGenerator{
+		opts: o,
+	}, nil
+}
+
+// Generate validates every row and writes the metadata files, none when a row is invalid
+// or references a missing image. It returns the number of files written.
+func (g *MetaGenerator) Generate() (int, error) {
+	file, err := os.Open(g.opts.CSVPath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	if len(records) < 2 {
+		return 0, fmt.Errorf("%s: no token rows", g.opts.CSVPath)
+	}
+
+	idColumn, columns, err := parseMetaColumns(records[0])
+	if err != nil {
+		return 0, err
+	}
+
+	imageDir, err := getSavePath(g.opts.ImageDir)
+	if err != nil {
+		return 0, err
+	}
+
+	metas := make(map[string]*MetaData, len(records)-1)
+	ids := make([]string, 0, len(records)-1)
+	var errs []string
+	for i, record := range records[1:] {
+		line := i + 2
+		id := strconv.Itoa(g.opts.StartTokenID + i)
+		if idColumn >= 0 {
+			id = strings.TrimSpace(record[idColumn])
+		}
+		if id == "" {
+			errs = append(errs, fmt.Sprintf("line %d: empty id", line))
+			continue
+		}
+		// the id names the metadata file, anything but digits would name another file
+		if strings.TrimLeft(id, "0123456789") != "" {
+			errs = append(errs, fmt.Sprintf("line %d: id %s is not a non-negative integer", line, id))
+			continue
+		}
+		if _, ok := metas[id]; ok {
+			errs = append(errs, fmt.Sprintf("line %d: duplicate id %s", line, id))
+			continue
+		}
+
+		m, err := metaFromRecord(columns, record)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+
+		if m.Image == "" {
+			errs = append(errs, fmt.Sprintf("line %d: empty image", line))
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(imageDir, filepath.Base(m.Image))); err != nil {
+			errs = append(errs, fmt.Sprintf("line %d: image %s not found in %s", line, m.Image, g.opts.ImageDir))
+			continue
+		}
+		if len(g.opts.ImageBaseURL) > 0 {
+			if m.Image, err = url.JoinPath(g.opts.ImageBaseURL, filepath.Base(m.Image)); err != nil {
+				return 0, err
+			}
+		}
+
+		metas[id] = m
+		ids = append(ids, id)
+	}
+	if len(errs) > 0 {
+		return 0, fmt.Errorf("%s:\n%s", g.opts.CSVPath, strings.Join(errs, "\n"))
+	}
+
+	outputDir, err := getSavePath(g.opts.OutputDir)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		metaByte, err := json.Marshal(metas[id])
+		if err != nil {
+			return 0, err
+		}
+
+		if err := saveJson(metaByte, outputDir, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(ids), nil
+}
+
+func parseMetaColumns(header []string) (int, []metaColumn, error) {
+	idColumn := -1
+	columns := make([]metaColumn, 0, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+
+		if key == "id" || key == "token_id" {
+			idColumn = i
+			continue
+		}
+		if field, ok := metaColumns[key]; ok {
+			columns = append(columns, metaColumn{index: i, field: field})
+			continue
+		}
+
+		traitType, displayType, _ := strings.Cut(name, ":")
+		if traitType == "" {
+			return 0, nil, fmt.Errorf("column %d: empty trait type", i+1)
+		}
+		if displayType != "" && !numericDisplayTypes[displayType] {
+			return 0, nil, fmt.Errorf("column %s: unknown type %s, expected number, boost_number, boost_percentage or date", name, displayType)
+		}
+		columns = append(columns, metaColumn{index: i, traitType: traitType, displayType: displayType})
+	}
+
+	return idColumn, columns, nil
+}
+
+func metaFromRecord(columns []metaColumn, record []string) (*MetaData, error) {
+	m := &MetaData{Attributes: []Attribute{}}
+	for _, column := range columns {
+		value := strings.TrimSpace(record[column.index])
+		if column.field != nil {
+			column.field(m, value)
+			continue
+		}
+
+		// tokens without the trait leave the cell empty
+		if value == "" {
+			continue
+		}
+
+		attribute := Attribute{TraitType: column.traitType, DisplayType: column.displayType, Value: value}
+		switch column.displayType {
+		case "":
+		case "date":
+			date, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", column.traitType, err)
+			}
+			attribute.Value = date
+		default:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: not a number: %s", column.traitType, value)
+			}
+			attribute.Value = number
+		}
+		m.Attributes = append(m.Attributes, attribute)
+	}
+
+	return m, nil
+}
+
+// parseDate returns the unix timestamp in seconds of a date given as a timestamp or in one of dateLayouts.
+func parseDate(value string) (int64, error) {
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Unix(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("not a date: %s", value)
+}
@@ -0,0 +1,111 @@
+package k0yote3web
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaFromRecord(t *testing.T) {
+	idColumn, columns, err := parseMetaColumns([]string{"token_id", "Name", "image", "Eyes", "Level:number", "Birthday:date"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, idColumn)
+
+	m, err := metaFromRecord(columns, []string{"7", "Foo #7", "7.png", "Blue", "3", "2019-01-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, &MetaData{
+		Name:  "Foo #7",
+		Image: "7.png",
+		Attributes: []Attribute{
+			{TraitType: "Eyes", Value: "Blue"},
+			{TraitType: "Level", DisplayType: "number", Value: float64(3)},
+			{TraitType: "Birthday", DisplayType: "date", Value: int64(1546300800)},
+		},
+	}, m)
+
+	m, err = metaFromRecord(columns, []string{"8", "Foo #8", "8.png", "", "4", "1546300800"})
+	assert.NoError(t, err)
+	assert.Len(t, m.Attributes, 2)
+
+	_, err = metaFromRecord(columns, []string{"9", "Foo #9", "9.png", "Red", "high", ""})
+	assert.EqualError(t, err, "Level: not a number: high")
+
+	_, _, err = parseMetaColumns([]string{"name", "Rank:ranking"})
+	assert.ErrorContains(t, err, "unknown type ranking")
+
+	_, err = newMetaGenerator(&GenerateMetaOptions{})
+	assert.Error(t, err)
+}
+
+func newTempMetaGenerator(t *testing.T, csv string, imageBaseURL string, startTokenID int) (*MetaGenerator, string) {
+	dir := t.TempDir()
+	imageDir := filepath.Join(dir, "image")
+	assert.NoError(t, os.Mkdir(imageDir, 0777))
+	for _, image := range []string{"1.png", "2.png"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(imageDir, image), []byte(image), 0644))
+	}
+	csvPath := filepath.Join(dir, "tokens.csv")
+	assert.NoError(t, os.WriteFile(csvPath, []byte(csv), 0644))
+
+	outputDir := filepath.Join(dir, "meta")
+	generator, err := newMetaGenerator(&GenerateMetaOptions{
+		CSVPath:      csvPath,
+		ImageDir:     imageDir,
+		OutputDir:    outputDir,
+		ImageBaseURL: imageBaseURL,
+		StartTokenID: startTokenID,
+	})
+	assert.NoError(t, err)
+
+	return generator, outputDir
+}
+
+func TestMetaGeneratorGenerate(t *testing.T) {
+	// without an id column the rows are numbered from StartTokenID
+	generator, outputDir := newTempMetaGenerator(t, "name,image,Eyes,Level:number\n"+
+		"Foo #5,1.png,Blue,3\n"+
+		"Foo #6,images/2.png,,4\n", "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/", 5)
+
+	count, err := generator.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	files, err := os.ReadDir(outputDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	b, err := os.ReadFile(filepath.Join(outputDir, "6"))
+	assert.NoError(t, err)
+	m := MetaData{}
+	assert.NoError(t, json.Unmarshal(b, &m))
+	assert.Equal(t, "Foo #6", m.Name)
+	assert.Equal(t, "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/2.png", m.Image)
+	assert.Equal(t, []Attribute{{TraitType: "Level", DisplayType: "number", Value: float64(4)}}, m.Attributes)
+}
+
+func TestMetaGeneratorGenerateInvalidRows(t *testing.T) {
+	generator, outputDir := newTempMetaGenerator(t, "id,name,image\n"+
+		"1,Foo #1,1.png\n"+
+		"1,Foo #1 again,2.png\n"+
+		",Foo,2.png\n"+
+		"3,Foo #3,3.png\n"+
+		"a/b,Foo,1.png\n"+
+		"1?x,Foo,1.png\n"+
+		"..,Foo,1.png\n"+
+		"-4,Foo,1.png\n", "", 0)
+
+	_, err := generator.Generate()
+	assert.ErrorContains(t, err, "line 3: duplicate id 1")
+	assert.ErrorContains(t, err, "line 4: empty id")
+	assert.ErrorContains(t, err, "line 5: image 3.png not found")
+	assert.ErrorContains(t, err, "line 6: id a/b is not a non-negative integer")
+	assert.ErrorContains(t, err, "line 7: id 1?x is not a non-negative integer")
+	assert.ErrorContains(t, err, "line 8: id .. is not a non-negative integer")
+	assert.ErrorContains(t, err, "line 9: id -4 is not a non-negative integer")
+
+	// the valid rows are not written either
+	assert.NoDirExists(t, outputDir)
+}
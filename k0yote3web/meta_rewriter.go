@@ -2,6 +2,7 @@ package k0yote3web
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -13,6 +14,16 @@ type MetaRewriter struct {
 	inputDir         string
 	outputDir        string
 	ipfsImageBaseURL string
+	rules            *MetaRules
+	// externalizeDataURIs replaces data uri images with their file uploaded under ipfsImageBaseURL
+	externalizeDataURIs bool
+}
+
+// RewritePreview is the metadata of a token before and after the rewrite.
+type RewritePreview struct {
+	File   string
+	Before map[string]any
+	After  map[string]any
 }
 
 func newMetaRewriter(ipfsImageBaseURL, inputDir, outputDir string) (*MetaRewriter, error) {
@@ -43,10 +54,69 @@ func newMetaRewriter(ipfsImageBaseURL, inputDir, outputDir string) (*MetaRewrite
 	}, nil
 }
 
+// SetRules makes the rewrite apply rules to every file after replacing the image url.
+func (r *MetaRewriter) SetRules(rules *MetaRules) {
+	r.rules = rules
+}
+
+// SetExternalizeDataURIs makes the rewrite replace the inline (data uri) images too, they are
+// kept on-chain style by default. The downloader saves them to the image folder to upload.
+func (r *MetaRewriter) SetExternalizeDataURIs(externalize bool) {
+	r.externalizeDataURIs = externalize
+}
+
 func (r *MetaRewriter) Rewrite() error {
 	return r.rewrite()
 }
 
+// Preview returns the changes the rewrite would make without writing any file.
+func (r *MetaRewriter) Preview() ([]*RewritePreview, error) {
+	metaDir, err := getSavePath(r.inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metaFiles, err := os.ReadDir(metaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	previews := make([]*RewritePreview, 0, len(metaFiles))
+	for _, metaFile := range metaFiles {
+		if metaFile.IsDir() {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(metaDir, metaFile.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		before := map[string]any{}
+		if err := json.Unmarshal(b, &before); err != nil {
+			return nil, fmt.Errorf("%s: %w", metaFile.Name(), err)
+		}
+
+		after, err := r.rewriteMeta(metaFile.Name(), b)
+		if err != nil {
+			return nil, err
+		}
+
+		afterMap := map[string]any{}
+		if err := json.Unmarshal(after, &afterMap); err != nil {
+			return nil, fmt.Errorf("%s: %w", metaFile.Name(), err)
+		}
+
+		previews = append(previews, &RewritePreview{
+			File:   metaFile.Name(),
+			Before: before,
+			After:  afterMap,
+		})
+	}
+
+	return previews, nil
+}
+
 func (r MetaRewriter) rewrite() error {
 	metaDir, err := getSavePath(r.inputDir)
 	if err != nil {
@@ -71,19 +141,7 @@ func (r MetaRewriter) rewrite() error {
 			return err
 		}
 
-		m := MetaData{}
-		if err := json.Unmarshal(b, &m); err != nil {
-			return err
-		}
-
-		filename := filepath.Base(m.Image)
-		newImagePath, err := url.JoinPath(r.ipfsImageBaseURL, filename)
-		if err != nil {
-			return err
-		}
-		m.Image = newImagePath
-
-		metaByte, err := json.Marshal(m)
+		metaByte, err := r.rewriteMeta(metaFile.Name(), b)
 		if err != nil {
 			return err
 		}
@@ -98,6 +156,54 @@ func (r MetaRewriter) rewrite() error {
 	return nil
 }
 
+// rewriteMeta replaces the image url of the metadata of file and applies the rules.
+func (r MetaRewriter) rewriteMeta(file string, b []byte) ([]byte, error) {
+	m := MetaData{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	if len(r.ipfsImageBaseURL) > 0 && (!isDataURI(m.Image) || r.externalizeDataURIs) {
+		filename, err := imageFilename(m.Image)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		newImagePath, err := url.JoinPath(r.ipfsImageBaseURL, filename)
+		if err != nil {
+			return nil, err
+		}
+		m.Image = newImagePath
+	}
+
+	metaByte, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if r.rules == nil {
+		return metaByte, nil
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal(metaByte, &fields); err != nil {
+		return nil, err
+	}
+	r.rules.Apply(file, fields)
+
+	return json.Marshal(fields)
+}
+
 func (r *MetaRewriter) Counter() int {
 	return counter
 }
+
+// Changes describes each field the rewrite changes, in field name order.
+func (p *RewritePreview) Changes() []string {
+	diffs := DiffMeta(p.Before, p.After)
+
+	changes := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		changes = append(changes, diff.String())
+	}
+
+	return changes
+}
@@ -1,6 +1,8 @@
 package k0yote3web
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,3 +17,22 @@ func TestRewriteMeta(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, helper.rewrite())
 }
+
+func TestRewriteMetaPreview(t *testing.T) {
+	inputDir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(inputDir, "old"), 0777))
+	assert.NoError(t, os.WriteFile(filepath.Join(inputDir, "1"), []byte(`{"name":"Foo #1","image":"https://example.com/1.png"}`), 0644))
+
+	helper, err := newMetaRewriter("ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", inputDir, t.TempDir())
+	assert.NoError(t, err)
+
+	// the subfolder is skipped
+	previews, err := helper.Preview()
+	assert.NoError(t, err)
+	assert.Len(t, previews, 1)
+	assert.Equal(t, "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png", previews[0].After["image"])
+
+	assert.NoError(t, os.WriteFile(filepath.Join(inputDir, "2"), []byte(`{"name":`), 0644))
+	_, err = helper.Preview()
+	assert.ErrorContains(t, err, "2: unexpected end of JSON input")
+}
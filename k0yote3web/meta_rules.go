@@ -0,0 +1,176 @@
+package k0yote3web
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+type RuleAction string
+
+const (
+	// RuleSet sets the field to Value, string values are templated
+	RuleSet RuleAction = "set"
+	// RuleDelete removes the field, or the whole matching attributes when Field is empty
+	RuleDelete RuleAction = "delete"
+	// RuleReplace replaces the matches of the Match regexp in a string field with Replace
+	RuleReplace RuleAction = "replace"
+	// RuleTemplate sets the field to the Template, e.g. "Foo #{id}"
+	RuleTemplate RuleAction = "template"
+	// RuleCase changes the case of a string field to Case
+	RuleCase RuleAction = "case"
+)
+
+// MetaRule is a transformation of a metadata field. Without Trait, Field is a top level field
+// such as name or external_url. With Trait, Field is a field of the attributes whose
+// trait_type is Trait ("*" for every attribute), e.g. trait_type to rename a trait.
+//
+// Templates and set strings expand {id} to the token id, the file name without its extension,
+// and {name}, {image} to the values of these fields before the rules ran.
+type MetaRule struct {
+	Action   RuleAction `yaml:"action" json:"action"`
+	Field    string     `yaml:"field" json:"field"`
+	Trait    string     `yaml:"trait,omitempty" json:"trait,omitempty"`
+	Value    any        `yaml:"value,omitempty" json:"value,omitempty"`
+	Match    string     `yaml:"match,omitempty" json:"match,omitempty"`
+	Replace  string     `yaml:"replace,omitempty" json:"replace,omitempty"`
+	Template string     `yaml:"template,omitempty" json:"template,omitempty"`
+	// Case is lower, upper or title
+	Case string `yaml:"case,omitempty" json:"case,omitempty"`
+
+	match *regexp.Regexp
+}
+
+type MetaRules struct {
+	Rules []*MetaRule `yaml:"rules" json:"rules"`
+}
+
+// LoadMetaRules reads the rules of a YAML or JSON file.
+func LoadMetaRules(path string) (*MetaRules, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is a subset of YAML, both are read by the YAML decoder
+	rules := &MetaRules{}
+	if err := yaml.Unmarshal(b, rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+func (r *MetaRules) compile() error {
+	for i, rule := range r.Rules {
+		if rule.Field == "" && !(rule.Action == RuleDelete && rule.Trait != "") {
+			return fmt.Errorf("rule %d: field is required", i)
+		}
+
+		switch rule.Action {
+		case RuleSet, RuleDelete:
+		case RuleReplace:
+			match, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return fmt.Errorf("rule %d: %w", i, err)
+			}
+			rule.match = match
+		case RuleTemplate:
+			if rule.Template == "" {
+				return fmt.Errorf("rule %d: template is required", i)
+			}
+		case RuleCase:
+			if rule.Case != "lower" && rule.Case != "upper" && rule.Case != "title" {
+				return fmt.Errorf("rule %d: unknown case: %s", i, rule.Case)
+			}
+		default:
+			return fmt.Errorf("rule %d: unknown action: %s", i, rule.Action)
+		}
+	}
+
+	return nil
+}
+
+// Apply runs the rules in order on the metadata of the token of file.
+func (r *MetaRules) Apply(file string, meta map[string]any) {
+	vars := strings.NewReplacer(
+		"{id}", tokenID(file),
+		"{name}", stringField(meta, "name"),
+		"{image}", stringField(meta, "image"),
+	)
+
+	for _, rule := range r.Rules {
+		if rule.Trait == "" {
+			rule.apply(vars, meta)
+			continue
+		}
+
+		attributes, _ := meta["attributes"].([]any)
+		kept := attributes[:0]
+		for _, a := range attributes {
+			attribute, ok := a.(map[string]any)
+			if !ok || (rule.Trait != "*" && attribute["trait_type"] != rule.Trait) {
+				kept = append(kept, a)
+				continue
+			}
+			if rule.Action == RuleDelete && rule.Field == "" {
+				continue
+			}
+			rule.apply(vars, attribute)
+			kept = append(kept, attribute)
+		}
+		if attributes != nil {
+			meta["attributes"] = kept
+		}
+	}
+}
+
+func (rule *MetaRule) apply(vars *strings.Replacer, fields map[string]any) {
+	switch rule.Action {
+	case RuleSet:
+		if s, ok := rule.Value.(string); ok {
+			fields[rule.Field] = vars.Replace(s)
+		} else {
+			fields[rule.Field] = rule.Value
+		}
+	case RuleDelete:
+		delete(fields, rule.Field)
+	case RuleTemplate:
+		fields[rule.Field] = vars.Replace(rule.Template)
+	case RuleReplace, RuleCase:
+		// numbers and booleans are left as they are, e.g. when casing the values of every trait
+		s, ok := fields[rule.Field].(string)
+		if !ok {
+			return
+		}
+		if rule.Action == RuleReplace {
+			fields[rule.Field] = rule.match.ReplaceAllString(s, vars.Replace(rule.Replace))
+		} else {
+			fields[rule.Field] = changeCase(s, rule.Case)
+		}
+	}
+}
+
+func changeCase(s, c string) string {
+	switch c {
+	case "lower":
+		return strings.ToLower(s)
+	case "upper":
+		return strings.ToUpper(s)
+	}
+	return cases.Title(language.Und).String(s)
+}
+
+func stringField(fields map[string]any, field string) string {
+	s, _ := fields[field].(string)
+	return s
+}
@@ -0,0 +1,66 @@
+package k0yote3web
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetaRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - {action: template, field: name, template: "Foo #{id}"}
+  - {action: set, field: external_url, value: "https://foo.xyz/token/{id}"}
+  - {action: delete, field: description}
+  - {action: replace, field: image, match: "^https://old\\.host/", replace: "ipfs://cid/"}
+  - {action: set, trait: Eyes, field: trait_type, value: Eye Color}
+  - {action: case, trait: "*", field: value, case: title}
+  - {action: set, trait: Level, field: display_type, value: number}
+  - {action: delete, trait: Background}
+`), 0644))
+
+	rules, err := LoadMetaRules(path)
+	assert.NoError(t, err)
+
+	meta := map[string]any{
+		"name":        "foo",
+		"description": "old description",
+		"image":       "https://old.host/7.png",
+		"attributes": []any{
+			map[string]any{"trait_type": "Eyes", "value": "light blue"},
+			map[string]any{"trait_type": "Level", "value": float64(3)},
+			map[string]any{"trait_type": "Background", "value": "red"},
+		},
+	}
+	rules.Apply("7.json", meta)
+	assert.Equal(t, map[string]any{
+		"name":         "Foo #7",
+		"external_url": "https://foo.xyz/token/7",
+		"image":        "ipfs://cid/7.png",
+		"attributes": []any{
+			map[string]any{"trait_type": "Eye Color", "value": "Light Blue"},
+			map[string]any{"trait_type": "Level", "value": float64(3), "display_type": "number"},
+		},
+	}, meta)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"rules": [{"action": "rename", "field": "name"}]}`), 0644))
+	_, err = LoadMetaRules(path)
+	assert.ErrorContains(t, err, "unknown action: rename")
+}
+
+func TestRewritePreviewChanges(t *testing.T) {
+	preview := &RewritePreview{
+		File:   "1.json",
+		Before: map[string]any{"name": "foo", "description": "bar", "image": "a.png"},
+		After:  map[string]any{"name": "Foo #1", "image": "a.png", "external_url": "https://foo.xyz"},
+	}
+
+	assert.Equal(t, []string{
+		`- description: "bar"`,
+		`+ external_url: "https://foo.xyz"`,
+		`~ name: "foo" -> "Foo #1"`,
+	}, preview.Changes())
+}
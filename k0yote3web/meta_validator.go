@@ -0,0 +1,329 @@
+package k0yote3web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// metaURISchemes are the schemes marketplaces resolve in image, animation_url and external_url
+	metaURISchemes = map[string]bool{"http": true, "https": true, "ipfs": true, "ar": true, "data": true}
+
+	// numericDisplayTypes are the OpenSea display types whose value must be a number
+	numericDisplayTypes = map[string]bool{"number": true, "boost_number": true, "boost_percentage": true, "date": true}
+
+	backgroundColorPattern = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+)
+
+// MetaIssue is a field of a metadata file breaking the ERC-721/ERC-1155 metadata schemas or
+// the OpenSea conventions.
+type MetaIssue struct {
+	// Field is the JSON path of the field, e.g. attributes[2].value
+	Field   string
+	Message string
+	// Warning marks issues marketplaces accept, e.g. a repeated trait_type, which do not
+	// make the file invalid
+	Warning bool
+}
+
+func (i MetaIssue) String() string {
+	if i.Warning {
+		return fmt.Sprintf("%s: warning: %s", i.Field, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+type MetaReport struct {
+	File   string
+	Issues []MetaIssue
+}
+
+// Valid reports whether the file has no issues other than warnings.
+func (r *MetaReport) Valid() bool {
+	for _, issue := range r.Issues {
+		if !issue.Warning {
+			return false
+		}
+	}
+	return true
+}
+
+type MetaValidator struct {
+	inputDir string
+}
+
+func newMetaValidator(inputDir string) (*MetaValidator, error) {
+	in := metadataFolderName
+	if len(inputDir) > 0 {
+		in = inputDir
+	}
+
+	if _, err := getSavePath(in); err != nil {
+		return nil, err
+	}
+
+	return &MetaValidator{
+		inputDir: in,
+	}, nil
+}
+
+// Validate checks every file of the input folder and returns a report per file, in file name order.
+func (v *MetaValidator) Validate() ([]*MetaReport, error) {
+	metaDir, err := getSavePath(v.inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metaFiles, err := os.ReadDir(metaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*MetaReport, 0, len(metaFiles))
+	for _, metaFile := range metaFiles {
+		if metaFile.IsDir() {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(metaDir, metaFile.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, &MetaReport{
+			File:   metaFile.Name(),
+			Issues: ValidateMetaData(b),
+		})
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].File < reports[j].File
+	})
+
+	return reports, nil
+}
+
+// ValidateMetaData checks the JSON metadata of a token and returns its issues and warnings,
+// none when it is valid.
+func ValidateMetaData(data []byte) []MetaIssue {
+	var meta map[string]any
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return []MetaIssue{{Field: "$", Message: fmt.Sprintf("invalid json: %v", err)}}
+	}
+
+	v := &metaChecker{meta: meta}
+	v.requiredString("name")
+	v.optionalString("description")
+	v.uri("image", true)
+	v.uri("animation_url", false)
+	v.uri("external_url", false)
+	v.youtubeURL()
+	v.backgroundColor()
+	v.properties()
+	v.attributes()
+
+	return v.issues
+}
+
+type metaChecker struct {
+	meta   map[string]any
+	issues []MetaIssue
+}
+
+func (v *metaChecker) add(field, format string, args ...any) {
+	v.issues = append(v.issues, MetaIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *metaChecker) warn(field, format string, args ...any) {
+	v.issues = append(v.issues, MetaIssue{Field: field, Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+func (v *metaChecker) requiredString(field string) (string, bool) {
+	value, ok := v.meta[field]
+	if !ok {
+		v.add(field, "is required")
+		return "", false
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		v.add(field, "must be a string, got %s", jsonType(value))
+		return "", false
+	}
+	if strings.TrimSpace(s) == "" {
+		v.add(field, "must not be empty")
+		return "", false
+	}
+
+	return s, true
+}
+
+func (v *metaChecker) optionalString(field string) (string, bool) {
+	if _, ok := v.meta[field]; !ok {
+		return "", false
+	}
+
+	return v.requiredString(field)
+}
+
+func (v *metaChecker) uri(field string, required bool) {
+	check := v.optionalString
+	if required {
+		check = v.requiredString
+	}
+
+	s, ok := check(field)
+	if !ok {
+		return
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || !metaURISchemes[u.Scheme] {
+		v.add(field, "must be an http(s), ipfs, ar or data uri: %s", s)
+		return
+	}
+	if u.Scheme != "data" && u.Host == "" {
+		v.add(field, "has no host or cid: %s", s)
+	}
+}
+
+func (v *metaChecker) youtubeURL() {
+	s, ok := v.optionalString("youtube_url")
+	if !ok {
+		return
+	}
+
+	if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		v.add("youtube_url", "must be an http(s) url: %s", s)
+	}
+}
+
+func (v *metaChecker) backgroundColor() {
+	s, ok := v.optionalString("background_color")
+	if !ok {
+		return
+	}
+
+	if !backgroundColorPattern.MatchString(s) {
+		v.add("background_color", "must be six hexadecimal digits without a leading #: %s", s)
+	}
+}
+
+func (v *metaChecker) properties() {
+	value, ok := v.meta["properties"]
+	if !ok {
+		return
+	}
+
+	if _, ok := value.(map[string]any); !ok {
+		v.add("properties", "must be an object, got %s", jsonType(value))
+	}
+}
+
+func (v *metaChecker) attributes() {
+	value, ok := v.meta["attributes"]
+	if !ok || value == nil {
+		return
+	}
+
+	attributes, ok := value.([]any)
+	if !ok {
+		v.add("attributes", "must be an array, got %s", jsonType(value))
+		return
+	}
+
+	traits := make(map[string]int, len(attributes))
+	for i, a := range attributes {
+		field := fmt.Sprintf("attributes[%d]", i)
+
+		attribute, ok := a.(map[string]any)
+		if !ok {
+			v.add(field, "must be an object, got %s", jsonType(a))
+			continue
+		}
+
+		if traitType, ok := attribute["trait_type"]; ok {
+			if s, ok := traitType.(string); !ok {
+				v.add(field+".trait_type", "must be a string, got %s", jsonType(traitType))
+			} else if first, ok := traits[s]; ok {
+				// repeated traits are shown as separate entries, analyze and export number them
+				v.warn(field+".trait_type", "duplicates attributes[%d]: %s", first, s)
+			} else {
+				traits[s] = i
+			}
+		}
+
+		value, ok := attribute["value"]
+		if !ok {
+			v.add(field+".value", "is required")
+			continue
+		}
+
+		displayType := ""
+		if dt, ok := attribute["display_type"]; ok {
+			if displayType, ok = dt.(string); !ok || !numericDisplayTypes[displayType] {
+				v.add(field+".display_type", "must be one of number, boost_number, boost_percentage, date: %v", dt)
+				displayType = ""
+			}
+		}
+
+		maxValue, hasMax := attribute["max_value"]
+		if displayType == "" && !hasMax {
+			switch value.(type) {
+			case string, float64, bool:
+			default:
+				v.add(field+".value", "must be a string, number or boolean, got %s", jsonType(value))
+			}
+			continue
+		}
+
+		number, ok := value.(float64)
+		if !ok {
+			v.add(field+".value", "must be a number, got %s", jsonType(value))
+			continue
+		}
+		if displayType == "date" && (number < 0 || number != float64(int64(number))) {
+			v.add(field+".value", "must be a unix timestamp in seconds: %v", value)
+		}
+
+		if !hasMax {
+			continue
+		}
+		if displayType == "date" {
+			v.add(field+".max_value", "is not allowed on a date")
+			continue
+		}
+		max, ok := maxValue.(float64)
+		if !ok {
+			v.add(field+".max_value", "must be a number, got %s", jsonType(maxValue))
+			continue
+		}
+		if number > max {
+			v.add(field+".value", "%v is above max_value %v", value, maxValue)
+		}
+	}
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,68 @@
+package k0yote3web
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMetaData(t *testing.T) {
+	valid := `{
+		"name": "Foo #1",
+		"description": "foo",
+		"image": "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1.png",
+		"external_url": "https://example.com/1",
+		"background_color": "00ff7f",
+		"attributes": [
+			{"trait_type": "Eyes", "value": "Blue"},
+			{"display_type": "boost_percentage", "trait_type": "Stamina", "value": 10},
+			{"display_type": "number", "trait_type": "Level", "value": 5, "max_value": 10},
+			{"display_type": "date", "trait_type": "Birthday", "value": 1546360800}
+		]
+	}`
+	assert.Empty(t, ValidateMetaData([]byte(valid)))
+
+	invalid := `{
+		"image": "/1.png",
+		"background_color": "#00ff7f",
+		"attributes": [
+			{"trait_type": "Eyes", "value": "Blue"},
+			{"trait_type": "Eyes", "value": "Red"},
+			{"display_type": "number", "trait_type": "Level", "value": "5"},
+			{"display_type": "ranking", "trait_type": "Rank", "value": 1},
+			{"trait_type": "Power", "value": 11, "max_value": 10}
+		]
+	}`
+	issues := ValidateMetaData([]byte(invalid))
+	fields := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		fields = append(fields, issue.Field)
+	}
+	assert.Equal(t, []string{
+		"name",
+		"image",
+		"background_color",
+		"attributes[1].trait_type",
+		"attributes[2].value",
+		"attributes[3].display_type",
+		"attributes[4].value",
+	}, fields)
+
+	assert.True(t, issues[3].Warning)
+	assert.Equal(t, "attributes[1].trait_type: warning: duplicates attributes[0]: Eyes", issues[3].String())
+	assert.False(t, (&MetaReport{Issues: issues}).Valid())
+
+	// a repeated trait type alone does not make the file invalid
+	repeated := ValidateMetaData([]byte(`{
+		"name": "Foo #2",
+		"image": "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/2.png",
+		"attributes": [
+			{"trait_type": "Accessory", "value": "Hat"},
+			{"trait_type": "Accessory", "value": "Scarf"}
+		]
+	}`))
+	assert.Len(t, repeated, 1)
+	assert.True(t, (&MetaReport{Issues: repeated}).Valid())
+
+	assert.Equal(t, "$", ValidateMetaData([]byte(`{"name":`))[0].Field)
+}
@@ -0,0 +1,251 @@
+package k0yote3web
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const defaultMulticallBatchSize = 500
+
+// multicall3Address is the address Multicall3 is deployed at on most chains.
+var multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+type MulticallOptions struct {
+	// Address of the Multicall3 contract, the deployment registered for the chain by default
+	Address common.Address
+	// BatchSize is the maximum number of calls packed into one aggregate3 call
+	BatchSize int
+}
+
+// Call is a single call packed into Multicall3 aggregate3.
+type Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+	// Value is the decoded return value for typed reads such as TokenURIs
+	Value any
+	// Err describes why the call failed or could not be decoded
+	Err error
+}
+
+type MulticallBatch struct {
+	Tx *types.Transaction
+	// Results are the per-call results simulated before sending
+	Results []CallResult
+}
+
+// Multicall packs many calls into Multicall3 aggregate3 calls and transactions.
+type Multicall struct {
+	helper    *contractHelper
+	address   common.Address
+	batchSize int
+	abi       abi.ABI
+	erc721    abi.ABI
+}
+
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+func newMulticall(handler *ProviderHandler, opts *MulticallOptions) (*Multicall, error) {
+	helper, err := newContractHelper(handler)
+	if err != nil {
+		return nil, err
+	}
+
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, err
+	}
+	tokenABI, err := abi.JSON(strings.NewReader(erc721ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Multicall{
+		helper:    helper,
+		address:   multicall3Address,
+		batchSize: defaultMulticallBatchSize,
+		abi:       multicallABI,
+		erc721:    tokenABI,
+	}
+	// Use the deployment registered for the chain, keeping the canonical one for unknown chains
+	if chain, err := handler.GetChain(context.Background()); err == nil && chain.Multicall3 != (common.Address{}) {
+		m.address = chain.Multicall3
+	}
+	if opts != nil {
+		if opts.Address != (common.Address{}) {
+			m.address = opts.Address
+		}
+		if opts.BatchSize > 0 {
+			m.batchSize = opts.BatchSize
+		}
+	}
+
+	return m, nil
+}
+
+// Call executes calls through eth_call in batches of BatchSize, reporting failures per call.
+func (m *Multicall) Call(ctx context.Context, calls []Call) ([]CallResult, error) {
+	results := make([]CallResult, 0, len(calls))
+	for _, batch := range m.batches(calls) {
+		batchResults, err := m.simulate(ctx, common.Address{}, batch)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batchResults...)
+	}
+
+	return results, nil
+}
+
+// Transact sends calls as aggregate3 transactions in batches of BatchSize. Each batch is
+// simulated first and not sent when a call that does not allow failure would revert.
+//
+// The calls are made by the Multicall3 contract, so only contracts that let it call
+// owner functions (e.g. through a granted role) can be updated this way.
+func (m *Multicall) Transact(opts *bind.TransactOpts, calls []Call) ([]MulticallBatch, error) {
+	batches := make([]MulticallBatch, 0)
+	for _, batch := range m.batches(calls) {
+		results, err := m.simulate(opts.Context, opts.From, batch)
+		if err != nil {
+			return batches, err
+		}
+		for i, result := range results {
+			if !result.Success && !batch[i].AllowFailure {
+				return batches, fmt.Errorf("call %d to %s would fail: %v", len(batches)*m.batchSize+i, batch[i].Target.Hex(), result.Err)
+			}
+		}
+
+		input, err := m.abi.Pack("aggregate3", batch)
+		if err != nil {
+			return batches, err
+		}
+
+		tx, err := m.helper.transact(opts, &m.address, input)
+		if err != nil {
+			return batches, err
+		}
+
+		batches = append(batches, MulticallBatch{
+			Tx:      tx,
+			Results: results,
+		})
+	}
+
+	return batches, nil
+}
+
+// TokenURIs reads tokenURI of tokenIDs on an ERC-721 contract, the decoded value is a string.
+func (m *Multicall) TokenURIs(ctx context.Context, contract common.Address, tokenIDs []*big.Int) ([]CallResult, error) {
+	return m.typedCall(ctx, contract, "tokenURI", tokenIDs)
+}
+
+// OwnersOf reads ownerOf of tokenIDs on an ERC-721 contract, the decoded value is a common.Address.
+func (m *Multicall) OwnersOf(ctx context.Context, contract common.Address, tokenIDs []*big.Int) ([]CallResult, error) {
+	return m.typedCall(ctx, contract, "ownerOf", tokenIDs)
+}
+
+// SetTokenURICall builds the call updating the URI of tokenID for Transact.
+func (m *Multicall) SetTokenURICall(contract common.Address, tokenID *big.Int, uri string) (Call, error) {
+	input, err := m.erc721.Pack("setTokenURI", tokenID, uri)
+	if err != nil {
+		return Call{}, err
+	}
+
+	return Call{Target: contract, CallData: input}, nil
+}
+
+func (m *Multicall) typedCall(ctx context.Context, contract common.Address, method string, tokenIDs []*big.Int) ([]CallResult, error) {
+	calls := make([]Call, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		input, err := m.erc721.Pack(method, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, Call{Target: contract, AllowFailure: true, CallData: input})
+	}
+
+	results, err := m.Call(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		if !results[i].Success {
+			continue
+		}
+		values, err := m.erc721.Unpack(method, results[i].ReturnData)
+		if err != nil {
+			results[i].Success = false
+			results[i].Err = fmt.Errorf("cannot decode %s of token %v: %v", method, tokenIDs[i], err)
+			continue
+		}
+		results[i].Value = values[0]
+	}
+
+	return results, nil
+}
+
+func (m *Multicall) simulate(ctx context.Context, from common.Address, calls []Call) ([]CallResult, error) {
+	input, err := m.abi.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := m.helper.provider.CallContract(ensureContext(ctx), ethereum.CallMsg{
+		From: from,
+		To:   &m.address,
+		Data: input,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := m.abi.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, err
+	}
+	decoded := *abi.ConvertType(values[0], new([]multicallResult)).(*[]multicallResult)
+
+	results := make([]CallResult, 0, len(decoded))
+	for _, r := range decoded {
+		result := CallResult{
+			Success:    r.Success,
+			ReturnData: r.ReturnData,
+		}
+		if !r.Success {
+			result.Err = DecodeRevert(r.ReturnData)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (m *Multicall) batches(calls []Call) [][]Call {
+	batches := make([][]Call, 0)
+	for start := 0; start < len(calls); start += m.batchSize {
+		end := start + m.batchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		batches = append(batches, calls[start:end])
+	}
+
+	return batches
+}
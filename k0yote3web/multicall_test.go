@@ -0,0 +1,86 @@
+package k0yote3web
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type callArgs struct {
+	From common.Address  `json:"from"`
+	To   *common.Address `json:"to"`
+	Data hexutil.Bytes   `json:"input"`
+}
+
+// stubMulticall answers aggregate3 eth_calls as an ERC-721 whose token 0 does not exist.
+type stubMulticall struct {
+	multicall abi.ABI
+	erc721    abi.ABI
+	calls     int
+}
+
+func (s *stubMulticall) Call(args callArgs, block string) (hexutil.Bytes, error) {
+	s.calls++
+
+	values, err := s.multicall.Methods["aggregate3"].Inputs.Unpack(args.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	calls := *abi.ConvertType(values[0], new([]Call)).(*[]Call)
+
+	results := make([]multicallResult, 0, len(calls))
+	for _, call := range calls {
+		values, err := s.erc721.Methods["tokenURI"].Inputs.Unpack(call.CallData[4:])
+		if err != nil {
+			return nil, err
+		}
+		tokenID := values[0].(*big.Int)
+		if tokenID.Sign() == 0 {
+			revert, _ := hexutil.Decode("0x08c379a0" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"000000000000000000000000000000000000000000000000000000000000000d" +
+				"6e6f6e6578697374656e74000000000000000000000000000000000000000000")
+			results = append(results, multicallResult{Success: false, ReturnData: revert})
+			continue
+		}
+
+		data, err := s.erc721.Methods["tokenURI"].Outputs.Pack(fmt.Sprintf("ipfs://cid/%s.json", tokenID))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, multicallResult{Success: true, ReturnData: data})
+	}
+
+	return s.multicall.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+func TestMulticallTokenURIs(t *testing.T) {
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	assert.NoError(t, err)
+	tokenABI, err := abi.JSON(strings.NewReader(erc721ABI))
+	assert.NoError(t, err)
+
+	stub := &stubMulticall{multicall: multicallABI, erc721: tokenABI}
+	helper := newStubContractHelper(t, stub)
+
+	multicall, err := newMulticall(helper.ProviderHandler, &MulticallOptions{BatchSize: 2})
+	assert.NoError(t, err)
+
+	tokenIDs := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	results, err := multicall.TokenURIs(context.Background(), common.HexToAddress("0x1"), tokenIDs)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stub.calls)
+	assert.Len(t, results, 3)
+
+	assert.False(t, results[0].Success)
+	assert.ErrorContains(t, results[0].Err, "nonexistent")
+	assert.Equal(t, "ipfs://cid/1.json", results[1].Value)
+	assert.Equal(t, "ipfs://cid/2.json", results[2].Value)
+}
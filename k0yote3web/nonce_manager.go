@@ -0,0 +1,222 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type pendingNonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+type transactionByHashReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+type accountNonce struct {
+	next   uint64
+	synced bool
+	// inFlight maps handed out nonces to their transaction, the zero hash until broadcast
+	inFlight map[uint64]common.Hash
+	// released holds nonces handed out but never broadcast (or dropped), reused before next
+	released []uint64
+}
+
+type nonceRef struct {
+	address common.Address
+	nonce   uint64
+}
+
+// NonceManager hands out sequential nonces per signer address so that concurrent
+// transactions from the same account do not collide.
+type NonceManager struct {
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonce
+	txs      map[common.Hash]nonceRef
+}
+
+func newNonceManager() *NonceManager {
+	return &NonceManager{
+		accounts: make(map[common.Address]*accountNonce),
+		txs:      make(map[common.Hash]nonceRef),
+	}
+}
+
+// Next returns the next nonce for address, syncing with the pending nonce on first use
+// and filling released gaps before advancing.
+func (m *NonceManager) Next(ctx context.Context, reader pendingNonceReader, address common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account := m.account(address)
+	if !account.synced {
+		if err := m.resync(ctx, reader, address, account); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(account.released) > 0 {
+		nonce = account.released[0]
+		account.released = account.released[1:]
+	} else {
+		nonce = account.next
+		account.next++
+	}
+
+	account.inFlight[nonce] = common.Hash{}
+	return nonce, nil
+}
+
+// Track records a broadcast transaction so its nonce is considered in flight.
+func (m *NonceManager) Track(address common.Address, nonce uint64, hash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.account(address).inFlight[nonce] = hash
+	m.txs[hash] = nonceRef{address: address, nonce: nonce}
+}
+
+// Confirm marks the transaction and every lower nonce of the same account as mined.
+func (m *NonceManager) Confirm(hash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ref, ok := m.txs[hash]
+	if !ok {
+		return
+	}
+
+	account := m.account(ref.address)
+	for nonce, h := range account.inFlight {
+		if nonce <= ref.nonce {
+			delete(account.inFlight, nonce)
+			delete(m.txs, h)
+		}
+	}
+}
+
+// Release gives back a nonce whose transaction was never broadcast or has been dropped,
+// so that the next transaction fills the gap instead of getting stuck behind it.
+func (m *NonceManager) Release(address common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account := m.account(address)
+	if hash, ok := account.inFlight[nonce]; ok {
+		delete(account.inFlight, nonce)
+		delete(m.txs, hash)
+	}
+	if nonce >= account.next {
+		return
+	}
+	for _, released := range account.released {
+		if released == nonce {
+			return
+		}
+	}
+
+	account.released = append(account.released, nonce)
+	sort.Slice(account.released, func(i, j int) bool { return account.released[i] < account.released[j] })
+}
+
+// Resync reloads the pending nonce of address from the chain, dropping state the
+// chain has moved past and releasing nonces that are neither mined nor in flight.
+func (m *NonceManager) Resync(ctx context.Context, reader pendingNonceReader, address common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.resync(ctx, reader, address, m.account(address))
+}
+
+// ResyncSender resyncs the account that sent the tracked transaction hash, e.g. when it was not
+// mined in time and may have been dropped. It does nothing for a transaction it does not track.
+func (m *NonceManager) ResyncSender(ctx context.Context, reader pendingNonceReader, hash common.Hash) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ref, ok := m.txs[hash]
+	if !ok {
+		return nil
+	}
+
+	return m.resync(ctx, reader, ref.address, m.account(ref.address))
+}
+
+// InFlight returns the transactions of address that have been broadcast but not confirmed.
+func (m *NonceManager) InFlight(address common.Address) map[uint64]common.Hash {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ret := make(map[uint64]common.Hash)
+	for nonce, hash := range m.account(address).inFlight {
+		if hash != (common.Hash{}) {
+			ret[nonce] = hash
+		}
+	}
+
+	return ret
+}
+
+func (m *NonceManager) account(address common.Address) *accountNonce {
+	account, ok := m.accounts[address]
+	if !ok {
+		account = &accountNonce{inFlight: make(map[uint64]common.Hash)}
+		m.accounts[address] = account
+	}
+
+	return account
+}
+
+func (m *NonceManager) resync(ctx context.Context, reader pendingNonceReader, address common.Address, account *accountNonce) error {
+	pending, err := reader.PendingNonceAt(ensureContext(ctx), address)
+	if err != nil {
+		return err
+	}
+
+	for nonce, hash := range account.inFlight {
+		if nonce < pending {
+			delete(account.inFlight, nonce)
+			delete(m.txs, hash)
+		}
+	}
+
+	// A tracked transaction unknown to the node has been dropped from the pool
+	if txReader, ok := reader.(transactionByHashReader); ok {
+		for nonce, hash := range account.inFlight {
+			if hash == (common.Hash{}) {
+				continue
+			}
+			if _, _, err := txReader.TransactionByHash(ensureContext(ctx), hash); errors.Is(err, ethereum.NotFound) {
+				delete(account.inFlight, nonce)
+				delete(m.txs, hash)
+			}
+		}
+	}
+
+	released := make([]uint64, 0)
+	if pending < account.next {
+		for nonce := pending; nonce < account.next; nonce++ {
+			if _, ok := account.inFlight[nonce]; !ok {
+				released = append(released, nonce)
+			}
+		}
+	} else {
+		account.next = pending
+	}
+
+	account.released = released
+	account.synced = true
+	return nil
+}
+
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
@@ -0,0 +1,158 @@
+package k0yote3web
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNonceReader struct {
+	pending uint64
+	known   map[common.Hash]bool
+}
+
+func (r *fakeNonceReader) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return r.pending, nil
+}
+
+func (r *fakeNonceReader) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if r.known[hash] {
+		return nil, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func TestNonceManagerConcurrentNext(t *testing.T) {
+	var (
+		m       = newNonceManager()
+		reader  = &fakeNonceReader{pending: 5}
+		address = common.HexToAddress("0x1")
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		seen    = make(map[uint64]bool)
+	)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Next(context.Background(), reader, address)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.False(t, seen[nonce])
+			seen[nonce] = true
+		}()
+	}
+	wg.Wait()
+
+	for nonce := uint64(5); nonce < 55; nonce++ {
+		assert.True(t, seen[nonce])
+	}
+}
+
+func TestNonceManagerReleaseFillsGap(t *testing.T) {
+	m := newNonceManager()
+	reader := &fakeNonceReader{pending: 0}
+	address := common.HexToAddress("0x1")
+
+	for i := uint64(0); i < 3; i++ {
+		nonce, err := m.Next(context.Background(), reader, address)
+		assert.NoError(t, err)
+		assert.Equal(t, i, nonce)
+	}
+
+	m.Release(address, 1)
+	nonce, err := m.Next(context.Background(), reader, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce)
+
+	nonce, err = m.Next(context.Background(), reader, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+}
+
+func TestNonceManagerResync(t *testing.T) {
+	m := newNonceManager()
+	reader := &fakeNonceReader{pending: 0, known: make(map[common.Hash]bool)}
+	address := common.HexToAddress("0x1")
+
+	for i := uint64(0); i < 4; i++ {
+		nonce, err := m.Next(context.Background(), reader, address)
+		assert.NoError(t, err)
+		hash := common.BigToHash(new(big.Int).SetUint64(nonce + 1))
+		m.Track(address, nonce, hash)
+		reader.known[hash] = true
+	}
+
+	// nonce 0 was mined, nonce 2 was dropped from the pool
+	reader.pending = 1
+	delete(reader.known, common.BigToHash(big.NewInt(3)))
+	assert.NoError(t, m.Resync(context.Background(), reader, address))
+	assert.Len(t, m.InFlight(address), 2)
+
+	nonce, err := m.Next(context.Background(), reader, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), nonce)
+
+	// another process sent transactions, the chain moved past our state
+	reader.pending = 10
+	assert.NoError(t, m.Resync(context.Background(), reader, address))
+	assert.Empty(t, m.InFlight(address))
+
+	nonce, err = m.Next(context.Background(), reader, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), nonce)
+
+	m.Track(address, nonce, common.HexToHash("0xabc"))
+	m.Confirm(common.HexToHash("0xabc"))
+	assert.Empty(t, m.InFlight(address))
+}
+
+// stubDroppedChain never mines and knows none of the transactions, they were dropped from the pool.
+type stubDroppedChain struct {
+	pending uint64
+}
+
+func (s *stubDroppedChain) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	return nil, nil
+}
+
+func (s *stubDroppedChain) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	return nil
+}
+
+func (s *stubDroppedChain) GetTransactionCount(account common.Address, block string) hexutil.Uint64 {
+	return hexutil.Uint64(s.pending)
+}
+
+func TestNonceManagerResyncAfterDroppedTx(t *testing.T) {
+	helper := newStubContractHelper(t, &stubDroppedChain{pending: 5})
+	address := common.HexToAddress("0x1")
+	dropped := common.HexToHash("0xdead")
+
+	nonce, err := helper.nonces.Next(context.Background(), helper.provider, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+	helper.nonces.Track(address, nonce, dropped)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = helper.WaitForReceipt(ctx, dropped, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the wait timed out, the nonce of the dropped transaction is handed out again
+	assert.Empty(t, helper.nonces.InFlight(address))
+	nonce, err = helper.nonces.Next(context.Background(), helper.provider, address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+}
@@ -0,0 +1,109 @@
+package k0yote3web
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var errMultipleKeySources = errors.New("only one of private key, keystore or mnemonic can be specified")
+
+// resolvePrivateKey returns the hex encoded private key described by the options,
+// decrypting a keystore file or deriving it from a mnemonic when required.
+func resolvePrivateKey(options *SDKOptions) (string, error) {
+	if options == nil {
+		return "", nil
+	}
+
+	sources := 0
+	for _, source := range []string{options.PrivateKey, options.KeystorePath, options.Mnemonic} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", errMultipleKeySources
+	}
+
+	var (
+		key *ecdsa.PrivateKey
+		err error
+	)
+	switch {
+	case options.KeystorePath != "":
+		key, err = loadKeystore(options.KeystorePath, options.KeystorePassphrase)
+	case options.Mnemonic != "":
+		key, err = deriveFromMnemonic(options.Mnemonic, options.MnemonicPassphrase, options.DerivationPath)
+	default:
+		return options.PrivateKey, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(crypto.FromECDSA(key)), nil
+}
+
+func loadKeystore(path, passphrase string) (*ecdsa.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(b, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %v", path, err)
+	}
+
+	return key.PrivateKey, nil
+}
+
+// deriveFromMnemonic derives a secp256k1 key from a BIP-39 mnemonic following BIP-32,
+// using the default ethereum path m/44'/60'/0'/0/0 when derivationPath is empty.
+func deriveFromMnemonic(mnemonic, passphrase, derivationPath string) (*ecdsa.PrivateKey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %v", err)
+	}
+
+	path := accounts.DefaultBaseDerivationPath
+	if derivationPath != "" {
+		if path, err = accounts.ParseDerivationPath(derivationPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return deriveFromSeed(seed, path)
+}
+
+// deriveFromSeed derives the BIP-32 key of path from the master key of seed.
+func deriveFromSeed(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	// The network only sets the version bytes of serialized keys, which are not used
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range path {
+		if key, err = key.Derive(index); err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %v", path, err)
+		}
+	}
+
+	priv, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.ToECDSA(priv.Serialize())
+}
@@ -0,0 +1,96 @@
+package k0yote3web
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveFromMnemonic(t *testing.T) {
+	key, err := deriveFromMnemonic(testMnemonic, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), crypto.PubkeyToAddress(key.PublicKey))
+
+	key, err = deriveFromMnemonic(testMnemonic, "", "m/44'/60'/0'/0/1")
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), crypto.PubkeyToAddress(key.PublicKey))
+
+	_, err = deriveFromMnemonic("test test test", "", "")
+	assert.Error(t, err)
+}
+
+// TestDeriveFromSeed checks the private keys of the BIP-32 test vectors 1 and 3, the latter
+// with a master key starting with a zero byte.
+func TestDeriveFromSeed(t *testing.T) {
+	tests := []struct {
+		seed string
+		path string
+		want string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", "m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+		{"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be", "m", "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32"},
+		{"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be", "m/0'", "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef"},
+	}
+
+	for _, tt := range tests {
+		var path accounts.DerivationPath
+		if tt.path != "m" {
+			var err error
+			path, err = accounts.ParseDerivationPath(tt.path)
+			assert.NoError(t, err)
+		}
+
+		key, err := deriveFromSeed(common.FromHex(tt.seed), path)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, common.Bytes2Hex(crypto.FromECDSA(key)), tt.path)
+	}
+}
+
+func TestLoadKeystore(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	b, err := keystore.EncryptKey(key, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	assert.NoError(t, os.WriteFile(path, b, 0600))
+
+	got, err := loadKeystore(path, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address, crypto.PubkeyToAddress(got.PublicKey))
+
+	_, err = loadKeystore(path, "wrong")
+	assert.Error(t, err)
+}
+
+func TestResolvePrivateKey(t *testing.T) {
+	privateKey, err := resolvePrivateKey(&SDKOptions{Mnemonic: testMnemonic})
+	assert.NoError(t, err)
+
+	handler, err := NewProviderHandler(nil, privateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), handler.GetSignerAddress())
+
+	_, err = resolvePrivateKey(&SDKOptions{PrivateKey: privateKey, Mnemonic: testMnemonic})
+	assert.ErrorIs(t, err, errMultipleKeySources)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -11,19 +12,28 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	kmscommon "github.com/thirdtool-dev/go-sdk/evmkms/common"
 )
 
 type ProviderHandler struct {
-	provider      *ethclient.Client
+	provider      Provider
 	privateKey    *ecdsa.PrivateKey
 	rawPrivateKey string
 	signerAddress common.Address
+	nonces        *NonceManager
+	// chain is the registered chain the provider is expected to serve, if any
+	chain *Chain
+	// gas prices the transactions without fees when set
+	gas *GasStrategy
+	// dryRun collects the reports of writes simulated instead of broadcast when set
+	dryRun *dryRunRecorder
 }
 
-func NewProviderHandler(provider *ethclient.Client, privateKey string) (*ProviderHandler, error) {
+func NewProviderHandler(provider Provider, privateKey string) (*ProviderHandler, error) {
 	handler := &ProviderHandler{
 		provider: provider,
+		nonces:   newNonceManager(),
 	}
 
 	if privateKey != "" {
@@ -35,7 +45,7 @@ func NewProviderHandler(provider *ethclient.Client, privateKey string) (*Provide
 	return handler, nil
 }
 
-func (handler *ProviderHandler) UpdateProvider(provider *ethclient.Client) {
+func (handler *ProviderHandler) UpdateProvider(provider Provider) {
 	handler.provider = provider
 }
 
@@ -47,7 +57,7 @@ func (handler *ProviderHandler) UpdatePrivateKey(privateKey string) error {
 	}
 }
 
-func (handler *ProviderHandler) GetProvider() *ethclient.Client {
+func (handler *ProviderHandler) GetProvider() Provider {
 	return handler.provider
 }
 
@@ -63,8 +73,83 @@ func (handler *ProviderHandler) GetPrivateKey() *ecdsa.PrivateKey {
 	return handler.privateKey
 }
 
+func (handler *ProviderHandler) GetNonceManager() *NonceManager {
+	return handler.nonces
+}
+
+// GetChainID returns the chain ID of the provider, failing when it is not the one of the
+// chain the handler was created for.
 func (handler *ProviderHandler) GetChainID(ctx context.Context) (*big.Int, error) {
-	return handler.provider.ChainID(ctx)
+	chainID, err := handler.provider.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if handler.chain != nil && handler.chain.GetChainID().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("provider serves chain ID %v, expected %s (%d)", chainID, handler.chain.Name, handler.chain.ChainID)
+	}
+
+	return chainID, nil
+}
+
+// GetChain returns the registered chain the provider serves.
+func (handler *ProviderHandler) GetChain(ctx context.Context) (*Chain, error) {
+	chainID, err := handler.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if handler.chain != nil {
+		return handler.chain, nil
+	}
+
+	return GetChainByID(chainID)
+}
+
+// GetTransactOpts returns transact options signing with the private key of the handler.
+func (handler *ProviderHandler) GetTransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	if handler.privateKey == nil {
+		return nil, errors.New("no private key to sign the transaction with")
+	}
+
+	signer, err := handler.getSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bind.TransactOpts{
+		Context: ctx,
+		From:    handler.signerAddress,
+		Signer:  signer,
+	}, nil
+}
+
+// SignMessage signs msg with the private key following EIP-191 (personal_sign).
+func (handler *ProviderHandler) SignMessage(msg []byte) ([]byte, error) {
+	return handler.signDigest(kmscommon.TextHash(msg))
+}
+
+// SignTypedData signs typedData with the private key following EIP-712.
+func (handler *ProviderHandler) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	digest, err := kmscommon.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.signDigest(digest)
+}
+
+func (handler *ProviderHandler) signDigest(digest common.Hash) ([]byte, error) {
+	if handler.privateKey == nil {
+		return nil, errors.New("no private key to sign the message with")
+	}
+
+	sig, err := crypto.Sign(digest[:], handler.privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return kmscommon.ToMessageSignature(sig)
 }
 
 func (handler *ProviderHandler) getSigner(ctx context.Context) (bind.SignerFn, error) {
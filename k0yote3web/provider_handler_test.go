@@ -0,0 +1,77 @@
+package k0yote3web
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	kmscommon "github.com/thirdtool-dev/go-sdk/evmkms/common"
+)
+
+func TestSignMessage(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	handler, err := NewProviderHandler(nil, EncodeToHex(crypto.FromECDSA(key)))
+	assert.NoError(t, err)
+
+	msg := []byte("hello")
+	sig, err := handler.SignMessage(msg)
+	assert.NoError(t, err)
+	assert.Contains(t, []byte{27, 28}, sig[crypto.RecoveryIDOffset])
+
+	from, err := kmscommon.RecoverMessageSigner(kmscommon.TextHash(msg), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, handler.GetSignerAddress(), from)
+
+	handler, err = NewProviderHandler(nil, "")
+	assert.NoError(t, err)
+	_, err = handler.SignMessage(msg)
+	assert.Error(t, err)
+}
+
+func TestSignTypedData(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	handler, err := NewProviderHandler(nil, EncodeToHex(crypto.FromECDSA(key)))
+	assert.NoError(t, err)
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Voucher": {
+				{Name: "tokenId", Type: "uint256"},
+				{Name: "uri", Type: "string"},
+			},
+		},
+		PrimaryType: "Voucher",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "LazyMint",
+			ChainId: math.NewHexOrDecimal256(1337),
+		},
+		Message: apitypes.TypedDataMessage{
+			"tokenId": "1",
+			"uri":     "ipfs://bafy/1.json",
+		},
+	}
+	sig, err := handler.SignTypedData(typedData)
+	assert.NoError(t, err)
+	assert.Contains(t, []byte{27, 28}, sig[crypto.RecoveryIDOffset])
+
+	digest, err := kmscommon.TypedDataHash(typedData)
+	assert.NoError(t, err)
+	from, err := kmscommon.RecoverMessageSigner(digest, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, handler.GetSignerAddress(), from)
+
+	// an unknown primary type can not be hashed
+	typedData.PrimaryType = "Order"
+	_, err = handler.SignTypedData(typedData)
+	assert.Error(t, err)
+}
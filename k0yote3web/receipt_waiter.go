@@ -0,0 +1,110 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const maxReceiptPollInterval = 16 * time.Second
+
+// waitMined waits until one of hashes is mined with confirmations blocks on top of it
+// (counting its own block). New heads are followed when the provider supports
+// subscriptions, otherwise receipts are polled with an exponential backoff. Without a
+// deadline on ctx the wait is bounded by txPollTimeout.
+func (c *contractHelper) waitMined(ctx context.Context, hashes []common.Hash, confirmations uint64) (*types.Receipt, error) {
+	ctx = ensureContext(ctx)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, txPollTimeout*txWaitTimeBetweenAttempts)
+		defer cancel()
+	}
+	if confirmations == 0 {
+		confirmations = 1
+	}
+
+	var (
+		heads   = make(chan *types.Header, 16)
+		subErr  <-chan error
+		polling = true
+	)
+	if sub, err := c.provider.SubscribeNewHead(ctx, heads); err == nil {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+		polling = false
+	}
+
+	interval := txWaitTimeBetweenAttempts
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		var headNumber *big.Int
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case head := <-heads:
+			headNumber = head.Number
+		case <-subErr:
+			// The subscription was dropped, keep waiting by polling
+			subErr, heads, polling = nil, nil, true
+			timer.Reset(interval)
+			continue
+		case <-timer.C:
+		}
+
+		receipt, err := c.confirmedReceipt(ctx, hashes, confirmations, headNumber)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			c.nonces.Confirm(receipt.TxHash)
+			return receipt, nil
+		}
+
+		if polling {
+			timer.Reset(interval)
+			if interval *= 2; interval > maxReceiptPollInterval {
+				interval = maxReceiptPollInterval
+			}
+		}
+	}
+}
+
+// confirmedReceipt returns the receipt of the first of hashes that is mined with enough
+// confirmations, or nil when none is yet.
+func (c *contractHelper) confirmedReceipt(ctx context.Context, hashes []common.Hash, confirmations uint64, headNumber *big.Int) (*types.Receipt, error) {
+	for _, hash := range hashes {
+		receipt, err := c.provider.TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if confirmations <= 1 {
+			return receipt, nil
+		}
+
+		if headNumber == nil {
+			number, err := c.provider.BlockNumber(ctx)
+			if err != nil {
+				return nil, err
+			}
+			headNumber = new(big.Int).SetUint64(number)
+		}
+
+		depth := new(big.Int).Sub(headNumber, receipt.BlockNumber)
+		if depth.Sign() >= 0 && depth.Uint64()+1 >= confirmations {
+			return receipt, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,179 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubChain struct {
+	mu       sync.Mutex
+	calls    int
+	minedAt  int
+	head     uint64
+	err      error
+	txHash   common.Hash
+	txNumber *big.Int
+}
+
+func (s *stubChain) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.calls < s.minedAt || hash != s.txHash {
+		return nil, nil
+	}
+	// every poll after the transaction is mined adds a block
+	s.head++
+
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      hash,
+		BlockNumber: s.txNumber,
+		Logs:        []*types.Log{},
+	}, nil
+}
+
+func (s *stubChain) BlockNumber() hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return hexutil.Uint64(s.head)
+}
+
+type stubHeadSubscription struct {
+	err chan error
+}
+
+func (s *stubHeadSubscription) Unsubscribe() {}
+
+func (s *stubHeadSubscription) Err() <-chan error {
+	return s.err
+}
+
+// subscribingProvider pushes heads to new head subscriptions, then fails them with err when set.
+type subscribingProvider struct {
+	Provider
+	heads []*types.Header
+	err   error
+}
+
+func (p *subscribingProvider) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	sub := &stubHeadSubscription{err: make(chan error, 1)}
+	go func() {
+		for _, head := range p.heads {
+			ch <- head
+		}
+		if p.err != nil {
+			sub.err <- p.err
+		}
+	}()
+
+	return sub, nil
+}
+
+// newStubContractHelper serves the eth namespace of stub over HTTP for a contract helper.
+func newStubContractHelper(t *testing.T, stub any) *contractHelper {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", stub))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	client, err := rpc.DialHTTP(httpServer.URL)
+	assert.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	handler, err := NewProviderHandler(ethclient.NewClient(client), "")
+	assert.NoError(t, err)
+	helper, err := newContractHelper(handler)
+	assert.NoError(t, err)
+
+	return helper
+}
+
+func TestWaitForReceiptPolling(t *testing.T) {
+	stub := &stubChain{minedAt: 2, head: 10, txHash: common.HexToHash("0x1"), txNumber: big.NewInt(10)}
+	helper := newStubContractHelper(t, stub)
+
+	receipt, err := helper.WaitForReceipt(context.Background(), stub.txHash, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.txHash, receipt.TxHash)
+	assert.Equal(t, 2, stub.calls)
+}
+
+func TestWaitForReceiptConfirmations(t *testing.T) {
+	stub := &stubChain{minedAt: 1, head: 9, txHash: common.HexToHash("0x1"), txNumber: big.NewInt(10)}
+	helper := newStubContractHelper(t, stub)
+
+	receipt, err := helper.WaitForReceipt(context.Background(), stub.txHash, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.txHash, receipt.TxHash)
+	assert.Equal(t, 2, stub.calls)
+}
+
+func TestWaitForReceiptError(t *testing.T) {
+	stub := &stubChain{err: errors.New("rpc unavailable"), txHash: common.HexToHash("0x1")}
+	helper := newStubContractHelper(t, stub)
+
+	_, err := helper.WaitForReceipt(context.Background(), stub.txHash, 1)
+	assert.ErrorContains(t, err, "rpc unavailable")
+}
+
+func TestWaitForReceiptDeadline(t *testing.T) {
+	stub := &stubChain{minedAt: 100, txHash: common.HexToHash("0x1")}
+	helper := newStubContractHelper(t, stub)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := helper.WaitForReceipt(ctx, stub.txHash, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForReceiptNewHeads(t *testing.T) {
+	stub := &stubChain{minedAt: 1, head: 10, txHash: common.HexToHash("0x1"), txNumber: big.NewInt(10)}
+	helper := newStubContractHelper(t, stub)
+	helper.UpdateProvider(&subscribingProvider{
+		Provider: helper.provider,
+		heads:    []*types.Header{{Number: big.NewInt(12)}},
+	})
+
+	start := time.Now()
+	receipt, err := helper.WaitForReceipt(context.Background(), stub.txHash, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.txHash, receipt.TxHash)
+	// checked once on start and once on the new head, without waiting for a poll
+	assert.Equal(t, 2, stub.calls)
+	assert.Less(t, time.Since(start), txWaitTimeBetweenAttempts)
+}
+
+func TestWaitForReceiptSubscriptionFallback(t *testing.T) {
+	stub := &stubChain{minedAt: 2, head: 10, txHash: common.HexToHash("0x1"), txNumber: big.NewInt(10)}
+	helper := newStubContractHelper(t, stub)
+	helper.UpdateProvider(&subscribingProvider{
+		Provider: helper.provider,
+		err:      errors.New("connection reset"),
+	})
+
+	// no head comes, the receipt is polled once the subscription failed
+	receipt, err := helper.WaitForReceipt(context.Background(), stub.txHash, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, stub.txHash, receipt.TxHash)
+	assert.Equal(t, 2, stub.calls)
+}
@@ -0,0 +1,307 @@
+package k0yote3web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type RevealOptions struct {
+	// InputDir is the final metadata to reveal, internal/upload by default
+	InputDir string
+	// ImageDir is where the images hashed into the provenance are, internal/image by default
+	ImageDir string
+	// PlaceholderDir is where the placeholder metadata is written, internal/placeholder by default
+	PlaceholderDir string
+	// OutputDir is where the shuffled metadata is written for the upload, internal/reveal by default
+	OutputDir string
+	// RecordDir is where the provenance and the reveal are recorded, internal by default
+	RecordDir string
+}
+
+// ProvenanceImage is the hash of the image of a metadata file.
+type ProvenanceImage struct {
+	File      string `json:"file"`
+	ImageHash string `json:"image_hash"`
+}
+
+// Provenance commits to the images of the collection before the seed is known: ProvenanceHash
+// is the sha256 of the concatenated sha256 hashes of the images in the order of the files.
+type Provenance struct {
+	ProvenanceHash string            `json:"provenance_hash"`
+	Images         []ProvenanceImage `json:"images"`
+}
+
+// RevealedToken is the metadata file the token was assigned by the shuffle.
+type RevealedToken struct {
+	TokenID int    `json:"token_id"`
+	File    string `json:"file"`
+}
+
+// RevealRecord is the seed of the shuffle and the file it assigned to every token, anyone
+// can check it against the provenance published before.
+type RevealRecord struct {
+	Seed   common.Hash     `json:"seed"`
+	Tokens []RevealedToken `json:"tokens"`
+}
+
+// Reveal generates the placeholder metadata of a drop, commits to its images with the provenance
+// and, once the seed is known, shuffles the final metadata to the token ids.
+type Reveal struct {
+	handler *ProviderHandler
+	opts    RevealOptions
+}
+
+func newReveal(handler *ProviderHandler, opts *RevealOptions) (*Reveal, error) {
+	o := RevealOptions{
+		InputDir:       uploadFolderName,
+		ImageDir:       imageFolderName,
+		PlaceholderDir: placeholderFolderName,
+		OutputDir:      revealFolderName,
+		RecordDir:      saveFolderName,
+	}
+	if opts != nil {
+		if len(opts.InputDir) > 0 {
+			o.InputDir = opts.InputDir
+		}
+		if len(opts.ImageDir) > 0 {
+			o.ImageDir = opts.ImageDir
+		}
+		if len(opts.PlaceholderDir) > 0 {
+			o.PlaceholderDir = opts.PlaceholderDir
+		}
+		if len(opts.OutputDir) > 0 {
+			o.OutputDir = opts.OutputDir
+		}
+		if len(opts.RecordDir) > 0 {
+			o.RecordDir = opts.RecordDir
+		}
+	}
+
+	return &Reveal{
+		handler: handler,
+		opts:    o,
+	}, nil
+}
+
+// GeneratePlaceholders writes the placeholder metadata of the tokens from startTokenID to
+// endTokenID, both included. {id} in the name and description is replaced with the token id.
+func (r *Reveal) GeneratePlaceholders(placeholder MetaData, startTokenID, endTokenID int) (int, error) {
+	if startTokenID > endTokenID {
+		return 0, fmt.Errorf("invalid token range: %d-%d", startTokenID, endTokenID)
+	}
+
+	outputDir, err := getSavePath(r.opts.PlaceholderDir)
+	if err != nil {
+		return 0, err
+	}
+
+	for id := startTokenID; id <= endTokenID; id++ {
+		m := placeholder
+		m.Name = strings.ReplaceAll(m.Name, "{id}", strconv.Itoa(id))
+		m.Description = strings.ReplaceAll(m.Description, "{id}", strconv.Itoa(id))
+
+		metaByte, err := json.Marshal(m)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := saveJson(metaByte, outputDir, strconv.Itoa(id)); err != nil {
+			return 0, err
+		}
+	}
+
+	return endTokenID - startTokenID + 1, nil
+}
+
+// SeedFromBlock returns the hash of a block to seed the shuffle, the latest one when number is nil.
+// Use a block mined after the sale so that nobody could know the order while minting.
+func (r *Reveal) SeedFromBlock(ctx context.Context, number *big.Int) (common.Hash, error) {
+	head, err := r.handler.GetProvider().HeaderByNumber(ensureContext(ctx), number)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return head.Hash(), nil
+}
+
+// Provenance hashes the images of the metadata of the input folder in token order and writes
+// the provenance next to the folders. Publish its hash before the seed is known.
+func (r *Reveal) Provenance() (*Provenance, error) {
+	inputDir, files, err := r.tokenFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	imageDir, err := getSavePath(r.opts.ImageDir)
+	if err != nil {
+		return nil, err
+	}
+
+	provenance := &Provenance{
+		Images: make([]ProvenanceImage, 0, len(files)),
+	}
+	hashes := sha256.New()
+	for _, file := range files {
+		b, err := os.ReadFile(filepath.Join(inputDir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		m := MetaData{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		filename, err := imageFilename(m.Image)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		image, err := os.ReadFile(filepath.Join(imageDir, filename))
+		if err != nil {
+			return nil, fmt.Errorf("%s: image of %s: %w", file, m.Image, err)
+		}
+		sum := sha256.Sum256(image)
+		imageHash := hex.EncodeToString(sum[:])
+		hashes.Write([]byte(imageHash))
+
+		provenance.Images = append(provenance.Images, ProvenanceImage{
+			File:      file,
+			ImageHash: imageHash,
+		})
+	}
+	provenance.ProvenanceHash = hex.EncodeToString(hashes.Sum(nil))
+
+	if err := r.record(provenanceFileName, provenance); err != nil {
+		return nil, err
+	}
+
+	return provenance, nil
+}
+
+// Reveal shuffles the metadata of the input folder with seed, writes it to the output folder
+// with token ids from startTokenID, and records the file every token was assigned.
+func (r *Reveal) Reveal(seed common.Hash, startTokenID int) (*RevealRecord, error) {
+	inputDir, files, err := r.tokenFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	outputDir, err := getSavePath(r.opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	shuffle(seed, files)
+
+	record := &RevealRecord{
+		Seed:   seed,
+		Tokens: make([]RevealedToken, 0, len(files)),
+	}
+	for i, file := range files {
+		b, err := os.ReadFile(filepath.Join(inputDir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		tokenID := startTokenID + i
+		if err := saveJson(b, outputDir, strconv.Itoa(tokenID)); err != nil {
+			return nil, err
+		}
+
+		record.Tokens = append(record.Tokens, RevealedToken{
+			TokenID: tokenID,
+			File:    file,
+		})
+	}
+
+	if err := r.record(revealFileName, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// tokenFiles returns the input folder and its metadata files in token order.
+func (r *Reveal) tokenFiles() (string, []string, error) {
+	inputDir, err := getSavePath(r.opts.InputDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	metaFiles, err := os.ReadDir(inputDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	files := make([]string, 0, len(metaFiles))
+	for _, metaFile := range metaFiles {
+		if !metaFile.IsDir() {
+			files = append(files, metaFile.Name())
+		}
+	}
+	sortTokenFiles(files)
+
+	return inputDir, files, nil
+}
+
+func (r *Reveal) record(filename string, v any) error {
+	recordDir, err := getSavePath(r.opts.RecordDir)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(recordDir, filename), b, 0644)
+}
+
+// shuffle is a Fisher-Yates shuffle drawing index i from keccak256(seed, i), so anyone can
+// replay it from the seed.
+func shuffle(seed common.Hash, files []string) {
+	for i := len(files) - 1; i > 0; i-- {
+		index := make([]byte, 8)
+		binary.BigEndian.PutUint64(index, uint64(i))
+
+		j := new(big.Int).SetBytes(crypto.Keccak256(seed.Bytes(), index))
+		j.Mod(j, big.NewInt(int64(i+1)))
+
+		files[i], files[j.Int64()] = files[j.Int64()], files[i]
+	}
+}
+
+// sortTokenFiles orders files by token id, numerically when the names are numbers.
+func sortTokenFiles(files []string) {
+	sort.Slice(files, func(i, j int) bool {
+		return tokenFileLess(files[i], files[j])
+	})
+}
+
+// tokenFileLess is a total order of token files, the provenance hash depends on it: numeric
+// names first by number, then the rest and equal numbers (e.g. 1 and 01.json) by name.
+func tokenFileLess(a, b string) bool {
+	x, errX := strconv.Atoi(tokenID(a))
+	y, errY := strconv.Atoi(tokenID(b))
+	switch {
+	case errX == nil && errY == nil && x != y:
+		return x < y
+	case (errX == nil) != (errY == nil):
+		return errX == nil
+	}
+	return a < b
+}
@@ -0,0 +1,128 @@
+package k0yote3web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubRevealChain struct{}
+
+func (s *stubRevealChain) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{Number: big.NewInt(42), Difficulty: new(big.Int)}
+}
+
+func TestRevealShuffle(t *testing.T) {
+	files := []string{"10.json", "2.json", "1.json", "3.json", "4.json"}
+	sortTokenFiles(files)
+	assert.Equal(t, []string{"1.json", "2.json", "3.json", "4.json", "10.json"}, files)
+
+	// mixed names are in the same order whatever order they are read in
+	mixed := []string{"b.json", "10.json", "01.json", "a", "2.json", "1.json", "1a.json"}
+	want := []string{"01.json", "1.json", "2.json", "10.json", "1a.json", "a", "b.json"}
+	for i := 0; i < len(mixed); i++ {
+		rotated := append(append([]string(nil), mixed[i:]...), mixed[:i]...)
+		sortTokenFiles(rotated)
+		assert.Equal(t, want, rotated)
+	}
+
+	seed := common.HexToHash("0x1234")
+	shuffled := append([]string(nil), files...)
+	shuffle(seed, shuffled)
+	assert.ElementsMatch(t, files, shuffled)
+	assert.NotEqual(t, files, shuffled)
+
+	again := append([]string(nil), files...)
+	shuffle(seed, again)
+	assert.Equal(t, shuffled, again)
+
+	other := append([]string(nil), files...)
+	shuffle(common.HexToHash("0x5678"), other)
+	assert.NotEqual(t, shuffled, other)
+}
+
+func TestRevealSeedFromBlock(t *testing.T) {
+	helper := newStubContractHelper(t, &stubRevealChain{})
+	reveal, err := newReveal(helper.ProviderHandler, nil)
+	assert.NoError(t, err)
+
+	seed, err := reveal.SeedFromBlock(context.Background(), big.NewInt(42))
+	assert.NoError(t, err)
+	assert.Equal(t, (&types.Header{Number: big.NewInt(42), Difficulty: new(big.Int)}).Hash(), seed)
+
+	_, err = reveal.GeneratePlaceholders(MetaData{}, 10, 1)
+	assert.ErrorContains(t, err, "invalid token range")
+}
+
+func TestRevealProvenanceAndReveal(t *testing.T) {
+	dir := t.TempDir()
+	opts := &RevealOptions{
+		InputDir:  filepath.Join(dir, "upload"),
+		ImageDir:  filepath.Join(dir, "image"),
+		OutputDir: filepath.Join(dir, "reveal"),
+		RecordDir: dir,
+	}
+	assert.NoError(t, os.Mkdir(opts.InputDir, 0777))
+	assert.NoError(t, os.Mkdir(opts.ImageDir, 0777))
+
+	hashes := sha256.New()
+	for id := 1; id <= 10; id++ {
+		meta := fmt.Sprintf(`{"name":"Token %d","image":"https://example.com/%d.png","attributes":[]}`, id, id)
+		assert.NoError(t, os.WriteFile(filepath.Join(opts.InputDir, fmt.Sprintf("%d.json", id)), []byte(meta), 0644))
+
+		image := []byte(fmt.Sprintf("image %d", id))
+		assert.NoError(t, os.WriteFile(filepath.Join(opts.ImageDir, fmt.Sprintf("%d.png", id)), image, 0644))
+		sum := sha256.Sum256(image)
+		hashes.Write([]byte(hex.EncodeToString(sum[:])))
+	}
+
+	reveal, err := newReveal(nil, opts)
+	assert.NoError(t, err)
+
+	// the provenance is in token order, it does not depend on the seed
+	provenance, err := reveal.Provenance()
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hashes.Sum(nil)), provenance.ProvenanceHash)
+	assert.Len(t, provenance.Images, 10)
+	assert.Equal(t, "1.json", provenance.Images[0].File)
+	assert.Equal(t, "10.json", provenance.Images[9].File)
+	assert.FileExists(t, filepath.Join(dir, provenanceFileName))
+
+	seed := common.HexToHash("0x1234")
+	record, err := reveal.Reveal(seed, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, seed, record.Seed)
+	assert.Len(t, record.Tokens, 10)
+
+	files := make([]string, 0, len(provenance.Images))
+	for _, image := range provenance.Images {
+		files = append(files, image.File)
+	}
+	shuffle(seed, files)
+	for i, token := range record.Tokens {
+		assert.Equal(t, i, token.TokenID)
+		assert.Equal(t, files[i], token.File)
+
+		want, err := os.ReadFile(filepath.Join(opts.InputDir, token.File))
+		assert.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(opts.OutputDir, fmt.Sprint(token.TokenID)))
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got))
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, revealFileName))
+	assert.NoError(t, err)
+	recorded := &RevealRecord{}
+	assert.NoError(t, json.Unmarshal(b, recorded))
+	assert.Equal(t, record, recorded)
+}
@@ -0,0 +1,164 @@
+package k0yote3web
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+var panicReasons = map[uint64]string{
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to a zero-initialized function",
+}
+
+// RevertError is a revert with an Error(string) reason, or with data that could not be decoded.
+type RevertError struct {
+	Reason string
+	Data   []byte
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason != "" {
+		return "execution reverted: " + e.Reason
+	}
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("execution reverted: %#x", e.Data)
+	}
+	return "execution reverted"
+}
+
+// PanicError is a Panic(uint256) raised by the compiler checks, e.g. on an arithmetic overflow.
+type PanicError struct {
+	Code *big.Int
+	Data []byte
+}
+
+func (e *PanicError) Error() string {
+	if reason, ok := panicReasons[e.Code.Uint64()]; e.Code.IsUint64() && ok {
+		return fmt.Sprintf("execution reverted: panic %#x (%s)", e.Code, reason)
+	}
+	return fmt.Sprintf("execution reverted: panic %#x", e.Code)
+}
+
+// CustomError is a revert with an error defined in a registered contract ABI, such as
+// OwnableUnauthorizedAccount(address).
+type CustomError struct {
+	Name string
+	// Args are the decoded arguments of the error, in order
+	Args []any
+	Data []byte
+}
+
+func (e *CustomError) Error() string {
+	args := make([]string, 0, len(e.Args))
+	for _, arg := range e.Args {
+		args = append(args, fmt.Sprint(arg))
+	}
+	return fmt.Sprintf("execution reverted: %s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+// IsCustomError reports whether err is the custom error named name.
+func IsCustomError(err error, name string) bool {
+	var customErr *CustomError
+	return errors.As(err, &customErr) && customErr.Name == name
+}
+
+var customErrors = struct {
+	sync.RWMutex
+	bySelector map[[4]byte]abi.Error
+}{bySelector: make(map[[4]byte]abi.Error)}
+
+func init() {
+	if err := RegisterErrors(openZeppelinErrorsABI); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterErrors makes the custom errors defined in the JSON contractABI decodable from revert data.
+func RegisterErrors(contractABI string) error {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return err
+	}
+
+	customErrors.Lock()
+	defer customErrors.Unlock()
+
+	for _, abiErr := range parsed.Errors {
+		var selector [4]byte
+		copy(selector[:], abiErr.ID[:4])
+		customErrors.bySelector[selector] = abiErr
+	}
+
+	return nil
+}
+
+// DecodeRevert decodes revert data into a *RevertError, *PanicError or *CustomError.
+func DecodeRevert(data []byte) error {
+	if len(data) < 4 {
+		return &RevertError{Data: data}
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return &RevertError{Reason: reason, Data: data}
+		}
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) == 4+32 {
+			return &PanicError{Code: new(big.Int).SetBytes(data[4:]), Data: data}
+		}
+	default:
+		var selector [4]byte
+		copy(selector[:], data[:4])
+
+		customErrors.RLock()
+		abiErr, ok := customErrors.bySelector[selector]
+		customErrors.RUnlock()
+
+		if ok {
+			if args, err := abiErr.Inputs.Unpack(data[4:]); err == nil {
+				return &CustomError{Name: abiErr.Name, Args: args, Data: data}
+			}
+		}
+	}
+
+	return &RevertError{Data: data}
+}
+
+// callError decodes the revert data carried by the error of a call, a gas estimation or a
+// sent transaction, returning other errors as they are.
+func callError(err error) error {
+	var dataErr rpc.DataError
+	if err == nil || !errors.As(err, &dataErr) {
+		return err
+	}
+
+	if data, ok := dataErr.ErrorData().(string); ok {
+		if revert, decodeErr := hexutil.Decode(data); decodeErr == nil && len(revert) > 0 {
+			return DecodeRevert(revert)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,56 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRevert(t *testing.T) {
+	reason := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6e6f706500000000000000000000000000000000000000000000000000000000")
+	var revertErr *RevertError
+	assert.True(t, errors.As(DecodeRevert(reason), &revertErr))
+	assert.Equal(t, "nope", revertErr.Reason)
+
+	overflow := append(crypto.Keccak256([]byte("Panic(uint256)"))[:4], common.LeftPadBytes([]byte{0x11}, 32)...)
+	var panicErr *PanicError
+	assert.True(t, errors.As(DecodeRevert(overflow), &panicErr))
+	assert.Equal(t, big.NewInt(0x11), panicErr.Code)
+	assert.EqualError(t, panicErr, "execution reverted: panic 0x11 (arithmetic underflow or overflow)")
+
+	account := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	unauthorized := append(crypto.Keccak256([]byte("OwnableUnauthorizedAccount(address)"))[:4], common.LeftPadBytes(account.Bytes(), 32)...)
+	err := DecodeRevert(unauthorized)
+	assert.True(t, IsCustomError(err, "OwnableUnauthorizedAccount"))
+	var customErr *CustomError
+	assert.True(t, errors.As(err, &customErr))
+	assert.Equal(t, account, customErr.Args[0])
+
+	unknown := hexutil.MustDecode("0xdeadbeef")
+	assert.EqualError(t, DecodeRevert(unknown), "execution reverted: 0xdeadbeef")
+
+	assert.NoError(t, RegisterErrors(`[{"inputs":[],"name":"SoldOut","type":"error"}]`))
+	soldOut := crypto.Keccak256([]byte("SoldOut()"))[:4]
+	assert.EqualError(t, DecodeRevert(soldOut), "execution reverted: SoldOut()")
+}
+
+func TestEstimateGasCustomError(t *testing.T) {
+	stub := &stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000)}
+	selector := crypto.Keccak256([]byte("EnforcedPause()"))[:4]
+	stub.revert = hexutil.Encode(selector)
+	wallet, helper := newStubWallet(t, stub)
+	opts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	_, err = wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+	assert.True(t, IsCustomError(err, "EnforcedPause"))
+}
@@ -0,0 +1,69 @@
+package k0yote3web
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getDefaultRpcUrl returns network as is when it is already an url, otherwise the url of
+// the network (e.g ethereum, sepolia, polygon, polygon-amoy) on the third party provider.
+// Registered chains are translated to the network name of the provider. Without a provider
+// only an empty network is a local node, any named one needs a provider.
+func getDefaultRpcUrl(network string, options *SDKOptions) (string, error) {
+	if isRpcUrl(network) {
+		return network, nil
+	}
+
+	provider := options.ThirdpartyProvier
+	chain, _ := GetChain(network)
+	if chain != nil {
+		if name, ok := chain.ProviderNetworks[provider]; ok {
+			network = name
+		}
+	}
+
+	switch provider {
+	case INFURA:
+		return fmt.Sprintf("https://%s.infura.io/v3/%s", network, options.ApiKey), nil
+	case ALCHEMY:
+		return fmt.Sprintf("https://%s.g.alchemy.com/v2/%s", network, options.ApiKey), nil
+	case QUICKNODE:
+		if options.QuickNodeEndpoint == "" {
+			return "", fmt.Errorf("quicknode requires the name of the endpoint")
+		}
+		host := options.QuickNodeEndpoint
+		if network != "" {
+			host += "." + network
+		}
+		return fmt.Sprintf("https://%s.quiknode.pro/%s/", host, options.ApiKey), nil
+	case ANKR:
+		if options.ApiKey == "" {
+			return fmt.Sprintf("https://rpc.ankr.com/%s", network), nil
+		}
+		return fmt.Sprintf("https://rpc.ankr.com/%s/%s", network, options.ApiKey), nil
+	case PUBLIC:
+		if chain == nil || len(chain.PublicRpcUrls) == 0 {
+			return "", fmt.Errorf("no public rpc url is known for %s", network)
+		}
+		return chain.PublicRpcUrls[0], nil
+	case "":
+		if network != "" {
+			return "", fmt.Errorf("no third party provider for %s, set one (e.g. public or local) or pass an rpc url", network)
+		}
+		return "http://localhost:8545", nil
+	case LOCAL:
+		return "http://localhost:8545", nil
+	default:
+		return "", fmt.Errorf("unknown third party provider: %s", provider)
+	}
+}
+
+func isRpcUrl(s string) bool {
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(strings.ToLower(s), scheme) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package k0yote3web
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDefaultRpcUrl(t *testing.T) {
+	tests := []struct {
+		network string
+		options SDKOptions
+		want    string
+	}{
+		{"arbitrum-sepolia", SDKOptions{ThirdpartyProvier: ALCHEMY, ApiKey: "key"}, "https://arb-sepolia.g.alchemy.com/v2/key"},
+		{"amoy", SDKOptions{ThirdpartyProvier: INFURA, ApiKey: "key"}, "https://polygon-amoy.infura.io/v3/key"},
+		{"custom", SDKOptions{ThirdpartyProvier: INFURA, ApiKey: "key"}, "https://custom.infura.io/v3/key"},
+		{"ethereum", SDKOptions{ThirdpartyProvier: QUICKNODE, QuickNodeEndpoint: "name", ApiKey: "token"}, "https://name.quiknode.pro/token/"},
+		{"polygon", SDKOptions{ThirdpartyProvier: QUICKNODE, QuickNodeEndpoint: "name", ApiKey: "token"}, "https://name.matic.quiknode.pro/token/"},
+		{"base-sepolia", SDKOptions{ThirdpartyProvier: ANKR}, "https://rpc.ankr.com/base_sepolia"},
+		{"base-sepolia", SDKOptions{ThirdpartyProvier: ANKR, ApiKey: "key"}, "https://rpc.ankr.com/base_sepolia/key"},
+		{"optimism", SDKOptions{ThirdpartyProvier: PUBLIC}, "https://mainnet.optimism.io"},
+		{"sepolia", SDKOptions{ThirdpartyProvier: LOCAL}, "http://localhost:8545"},
+		{"", SDKOptions{}, "http://localhost:8545"},
+		{"wss://node.example.com/ws", SDKOptions{ThirdpartyProvier: ALCHEMY}, "wss://node.example.com/ws"},
+	}
+
+	for _, tt := range tests {
+		got, err := getDefaultRpcUrl(tt.network, &tt.options)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := getDefaultRpcUrl("polygon", &SDKOptions{ThirdpartyProvier: "cloudflare"})
+	assert.ErrorContains(t, err, "unknown third party provider: cloudflare")
+
+	_, err = getDefaultRpcUrl("polygon", &SDKOptions{ThirdpartyProvier: QUICKNODE})
+	assert.Error(t, err)
+
+	_, err = getDefaultRpcUrl("custom", &SDKOptions{ThirdpartyProvier: PUBLIC})
+	assert.Error(t, err)
+
+	// a named network, known or a typo, is never sent to a local node without saying so
+	_, err = getDefaultRpcUrl("sepolia", &SDKOptions{})
+	assert.ErrorContains(t, err, "no third party provider for sepolia")
+	_, err = getDefaultRpcUrl("mainet", &SDKOptions{})
+	assert.ErrorContains(t, err, "no third party provider for mainet")
+}
@@ -1,9 +1,11 @@
 package k0yote3web
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type K0yote3WebSDK struct {
@@ -14,38 +16,73 @@ type K0yote3WebSDK struct {
 }
 
 func NewK0yote3WebSDK(rpcUrlOrChainName string, options *SDKOptions) (*K0yote3WebSDK, error) {
-	rpc := getDefaultRpcUrl(rpcUrlOrChainName, options.ApiKey, options.ThirdpartyProvier)
-
-	provider, err := ethclient.Dial(rpc)
+	// Unknown names are used as is in the provider url
+	chain, _ := GetChain(rpcUrlOrChainName)
+	rpcUrl, err := getDefaultRpcUrl(rpcUrlOrChainName, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewThirdwebSDKFromProvider(provider, options)
+	var provider Provider
+	if len(options.FailoverRpcUrls) > 0 {
+		urls := append([]string{rpcUrl}, options.FailoverRpcUrls...)
+		failoverOpts := &FailoverOptions{Headers: options.Headers}
+		if chain != nil {
+			failoverOpts.ChainID = chain.GetChainID()
+		}
+		failoverProvider, err := NewFailoverProvider(context.Background(), urls, failoverOpts)
+		if err != nil {
+			return nil, err
+		}
+		provider = failoverProvider
+	} else {
+		client, err := rpc.DialOptions(context.Background(), rpcUrl, rpc.WithHeaders(options.Headers))
+		if err != nil {
+			return nil, err
+		}
+		provider = ethclient.NewClient(client)
+	}
+
+	return newSDK(provider, options, chain)
 }
 
-func NewThirdwebSDKFromProvider(provider *ethclient.Client, options *SDKOptions) (*K0yote3WebSDK, error) {
-	privateKey := ""
+func NewThirdwebSDKFromProvider(provider Provider, options *SDKOptions) (*K0yote3WebSDK, error) {
+	return newSDK(provider, options, nil)
+}
 
+// newSDK creates the SDK on provider, checking it serves chain when given.
+func newSDK(provider Provider, options *SDKOptions, chain *Chain) (*K0yote3WebSDK, error) {
 	// Override defaults with the options that are defined
-	if options != nil {
-		if options.PrivateKey != "" {
-			privateKey = options.PrivateKey
-		}
+	privateKey, err := resolvePrivateKey(options)
+	if err != nil {
+		return nil, err
 	}
 
 	handler, err := NewProviderHandler(provider, privateKey)
 	if err != nil {
 		return nil, err
 	}
+	handler.chain = chain
+	if options != nil {
+		handler.SetDryRun(options.DryRun)
+		if options.GasStrategy != nil {
+			if handler.gas, err = newGasStrategy(handler, options.GasStrategy); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	deployer, err := newContractDeployer(provider, privateKey)
+	deployer, err := newContractDeployer(provider, privateKey, handler.GetNonceManager())
 	if err != nil {
 		return nil, err
 	}
 
 	if deployer == nil {
 		fmt.Println("Warning: Contract deployments are not supported on this network. SDK instantiated without a Deployer.")
+	} else {
+		deployer.chain = chain
+		deployer.gas = handler.gas
+		deployer.dryRun = handler.dryRun
 	}
 
 	sdk := &K0yote3WebSDK{
@@ -56,26 +93,59 @@ func NewThirdwebSDKFromProvider(provider *ethclient.Client, options *SDKOptions)
 	return sdk, nil
 }
 
-// network to (e.g mainnet, sepolia, polygon, polygon-mumbai)
-func getDefaultRpcUrl(network, apiKey string, thirdpartyProvider ThirdpartyProvider) string {
-	switch thirdpartyProvider {
-	case INFURA:
-		return fmt.Sprintf("https://%s.infura.io/v3/%s", network, apiKey)
-	case ALCHEMY:
-		return fmt.Sprintf("https://%s.g.alchemy.com/v2/%s", network, apiKey)
-	default:
-		return "http://localhost:8545"
+func (sdk *K0yote3WebSDK) GetDownload(opts *DownloadMetaOptions) (*Download, error) {
+	reader, err := newChainReader(sdk.ProviderHandler, nil)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (sdk *K0yote3WebSDK) GetDownload(opts *DownloadMetaOptions) (*Download, error) {
-	return newDownload(opts)
+	return newDownload(opts, reader)
 }
 
 func (sdk *K0yote3WebSDK) GetRewriter(ipfsImageBaseURL, inputDir, outputDir string) (*MetaRewriter, error) {
 	return newMetaRewriter(ipfsImageBaseURL, inputDir, outputDir)
 }
 
+func (sdk *K0yote3WebSDK) GetReveal(opts *RevealOptions) (*Reveal, error) {
+	return newReveal(sdk.ProviderHandler, opts)
+}
+
+func (sdk *K0yote3WebSDK) GetMetaDiffer(beforeDir, afterDir string) (*MetaDiffer, error) {
+	return newMetaDiffer(beforeDir, afterDir)
+}
+
+func (sdk *K0yote3WebSDK) GetMetaGenerator(opts *GenerateMetaOptions) (*MetaGenerator, error) {
+	return newMetaGenerator(opts)
+}
+
+func (sdk *K0yote3WebSDK) GetMetaExporter(opts *ExportOptions) (*MetaExporter, error) {
+	return newMetaExporter(opts)
+}
+
+func (sdk *K0yote3WebSDK) GetMetaValidator(inputDir string) (*MetaValidator, error) {
+	return newMetaValidator(inputDir)
+}
+
 func (sdk *K0yote3WebSDK) GetIpfsUploader(opts *IPFSOptions) (*IpfsUploader, error) {
 	return newIpfsUploader(opts)
 }
+
+func (sdk *K0yote3WebSDK) GetTxMonitor(opts *TxMonitorOptions) (*TxMonitor, error) {
+	return newTxMonitor(sdk.ProviderHandler, opts)
+}
+
+func (sdk *K0yote3WebSDK) GetMulticall(opts *MulticallOptions) (*Multicall, error) {
+	return newMulticall(sdk.ProviderHandler, opts)
+}
+
+func (sdk *K0yote3WebSDK) GetChainReader(opts *ChainReaderOptions) (*ChainReader, error) {
+	return newChainReader(sdk.ProviderHandler, opts)
+}
+
+func (sdk *K0yote3WebSDK) GetGasStrategy(opts *GasStrategyOptions) (*GasStrategy, error) {
+	return newGasStrategy(sdk.ProviderHandler, opts)
+}
+
+func (sdk *K0yote3WebSDK) GetWallet() (*Wallet, error) {
+	return newWallet(sdk.ProviderHandler)
+}
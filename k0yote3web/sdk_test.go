@@ -0,0 +1,20 @@
+package k0yote3web
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSDKFromProviderWithoutOptions(t *testing.T) {
+	client := rpc.DialInProc(rpc.NewServer())
+	t.Cleanup(client.Close)
+
+	sdk, err := NewThirdwebSDKFromProvider(ethclient.NewClient(client), nil)
+	assert.NoError(t, err)
+	assert.False(t, sdk.IsDryRun())
+	assert.Nil(t, sdk.gas)
+	assert.NotNil(t, sdk.Deployer)
+}
@@ -0,0 +1,324 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultReplaceAfter    = 3 * time.Minute
+	defaultMaxReplacements = 5
+	// minReplacementBump is the price bump in percent nodes require to replace a pending transaction
+	minReplacementBump = 10
+)
+
+type TxMonitorOptions struct {
+	// ReplaceAfter is how long to wait for a transaction before re-submitting it with bumped fees
+	ReplaceAfter time.Duration
+	// BumpPercent is the fee increase for each replacement, at least 10
+	BumpPercent int64
+	// MaxReplacements is how many times a transaction is re-submitted before giving up
+	MaxReplacements int
+	// Confirmations is the number of blocks, counting its own, a transaction needs to be final
+	Confirmations uint64
+}
+
+type TxMonitorResult struct {
+	// Hash is the hash of the transaction that was finally mined
+	Hash    common.Hash
+	Receipt *types.Receipt
+	// Submitted are all the hashes broadcast for the nonce, in order
+	Submitted []common.Hash
+}
+
+// TxMonitor waits for transactions and replaces the ones stuck in the pool.
+type TxMonitor struct {
+	helper *contractHelper
+	opts   TxMonitorOptions
+}
+
+func newTxMonitor(handler *ProviderHandler, opts *TxMonitorOptions) (*TxMonitor, error) {
+	helper, err := newContractHelper(handler)
+	if err != nil {
+		return nil, err
+	}
+
+	o := TxMonitorOptions{
+		ReplaceAfter:    defaultReplaceAfter,
+		BumpPercent:     minReplacementBump,
+		MaxReplacements: defaultMaxReplacements,
+	}
+	if opts != nil {
+		if opts.ReplaceAfter > 0 {
+			o.ReplaceAfter = opts.ReplaceAfter
+		}
+		if opts.BumpPercent > minReplacementBump {
+			o.BumpPercent = opts.BumpPercent
+		}
+		if opts.MaxReplacements > 0 {
+			o.MaxReplacements = opts.MaxReplacements
+		}
+		o.Confirmations = opts.Confirmations
+	}
+
+	return &TxMonitor{
+		helper: helper,
+		opts:   o,
+	}, nil
+}
+
+// Wait waits for txs, submissions sharing one nonce, to be mined. The last submission is
+// re-submitted with bumped fees every ReplaceAfter and the receipt of whichever was mined is returned.
+func (m *TxMonitor) Wait(opts *bind.TransactOpts, txs ...*types.Transaction) (*TxMonitorResult, error) {
+	return m.wait(opts, m.SpeedUp, txs)
+}
+
+// WaitSweep is Wait for the submissions of Wallet.Sweep, whose replacements send the
+// balance less the bumped fees.
+func (m *TxMonitor) WaitSweep(opts *bind.TransactOpts, txs ...*types.Transaction) (*TxMonitorResult, error) {
+	return m.wait(opts, m.SpeedUpSweep, txs)
+}
+
+func (m *TxMonitor) wait(opts *bind.TransactOpts, speedUp func(*bind.TransactOpts, *types.Transaction) (*types.Transaction, error), txs []*types.Transaction) (*TxMonitorResult, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transaction to wait for")
+	}
+
+	submitted := make([]common.Hash, 0, len(txs))
+	for _, tx := range txs {
+		submitted = append(submitted, tx.Hash())
+	}
+	current := txs[len(txs)-1]
+
+	for replacements := 0; ; replacements++ {
+		receipt, err := m.waitForAny(opts.Context, submitted, m.opts.ReplaceAfter)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return m.result(receipt, submitted), nil
+		}
+
+		if replacements >= m.opts.MaxReplacements {
+			m.helper.resyncDropped(current.Hash())
+			return nil, fmt.Errorf("transaction with nonce %d was not mined after %d replacements", current.Nonce(), replacements)
+		}
+
+		replacement, err := speedUp(opts, current)
+		if err != nil {
+			if isNonceTooLow(err) {
+				// One of the submissions was mined in the meantime
+				continue
+			}
+			return nil, err
+		}
+		log.Printf("replaced transaction %s with %s\n", current.Hash().Hex(), replacement.Hash().Hex())
+
+		current = replacement
+		submitted = append(submitted, replacement.Hash())
+	}
+}
+
+// SpeedUp re-submits tx with the same nonce and fees bumped by BumpPercent.
+func (m *TxMonitor) SpeedUp(opts *bind.TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	return m.replace(opts, tx, tx.To(), tx.Value(), tx.Data(), tx.Gas(), false)
+}
+
+// SpeedUpSweep re-submits a sweep of Wallet.Sweep with bumped fees. The value is the balance
+// less the bumped fees, the value of the sweep would not leave enough to pay for them.
+func (m *TxMonitor) SpeedUpSweep(opts *bind.TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	return m.replace(opts, tx, tx.To(), nil, nil, tx.Gas(), true)
+}
+
+// Cancel replaces tx with a 0-value transfer to the sender itself using the same nonce.
+func (m *TxMonitor) Cancel(opts *bind.TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	return m.replace(opts, tx, &opts.From, new(big.Int), nil, 21_000, false)
+}
+
+func (m *TxMonitor) replace(opts *bind.TransactOpts, tx *types.Transaction, to *common.Address, value *big.Int, data []byte, gas uint64, sweep bool) (*types.Transaction, error) {
+	if opts.Signer == nil {
+		return nil, fmt.Errorf("no signer to authorize the transaction with")
+	}
+	// Only a transaction of our own can be replaced at its nonce
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	if sender != opts.From {
+		return nil, fmt.Errorf("transaction %s is sent from %s, not from %s", tx.Hash().Hex(), sender.Hex(), opts.From.Hex())
+	}
+
+	var rawTx *types.Transaction
+	switch tx.Type() {
+	case types.LegacyTxType:
+		gasPrice, err := m.bumpedGasPrice(opts.Context, tx.GasPrice())
+		if err != nil {
+			return nil, err
+		}
+		rawTx = types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	default:
+		gasTipCap, gasFeeCap, err := m.bumpedDynamicFees(opts.Context, tx.GasTipCap(), tx.GasFeeCap())
+		if err != nil {
+			return nil, err
+		}
+		rawTx = types.NewTx(&types.DynamicFeeTx{
+			Nonce:     tx.Nonce(),
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		})
+	}
+
+	if sweep {
+		if rawTx, err = m.sweepValue(opts.Context, opts.From, rawTx); err != nil {
+			return nil, err
+		}
+	}
+
+	signedTx, err := opts.Signer(opts.From, rawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.helper.dryRun != nil {
+		return m.helper.simulateDryRun(opts, signedTx)
+	}
+	if err := m.helper.sendTransaction(opts.Context, signedTx); err != nil {
+		return nil, err
+	}
+	m.helper.nonces.Track(opts.From, signedTx.Nonce(), signedTx.Hash())
+
+	return signedTx, nil
+}
+
+// sweepValue sets the value of rawTx to the balance of from less the most rawTx can cost,
+// as createRawTransaction does for the sweep it replaces.
+func (m *TxMonitor) sweepValue(ctx context.Context, from common.Address, rawTx *types.Transaction) (*types.Transaction, error) {
+	ctx = ensureContext(ctx)
+	balance, err := m.helper.provider.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(rawTx.Gas()), rawTx.GasFeeCap())
+	l1Fee, err := m.helper.l1Fee(ctx, withValue(rawTx, balance))
+	if err != nil {
+		return nil, err
+	}
+	fee.Add(fee, l1Fee)
+
+	value := new(big.Int).Sub(balance, fee)
+	if value.Sign() <= 0 {
+		return nil, fmt.Errorf("not enough fund: balance %v does not cover the fee %v", balance, fee)
+	}
+
+	return withValue(rawTx, value), nil
+}
+
+// withValue is a copy of the transfer rawTx sending value.
+func withValue(rawTx *types.Transaction, value *big.Int) *types.Transaction {
+	if rawTx.Type() == types.LegacyTxType {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    rawTx.Nonce(),
+			GasPrice: rawTx.GasPrice(),
+			Gas:      rawTx.Gas(),
+			To:       rawTx.To(),
+			Value:    value,
+		})
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		Nonce:     rawTx.Nonce(),
+		GasTipCap: rawTx.GasTipCap(),
+		GasFeeCap: rawTx.GasFeeCap(),
+		Gas:       rawTx.Gas(),
+		To:        rawTx.To(),
+		Value:     value,
+	})
+}
+
+func (m *TxMonitor) bumpedGasPrice(ctx context.Context, gasPrice *big.Int) (*big.Int, error) {
+	suggested, err := m.helper.provider.SuggestGasPrice(ensureContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return bigMax(bumpFee(gasPrice, m.opts.BumpPercent), suggested), nil
+}
+
+func (m *TxMonitor) bumpedDynamicFees(ctx context.Context, gasTipCap, gasFeeCap *big.Int) (*big.Int, *big.Int, error) {
+	head, err := m.helper.provider.HeaderByNumber(ensureContext(ctx), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	suggestedTip, err := m.helper.provider.SuggestGasTipCap(ensureContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tip := bigMax(bumpFee(gasTipCap, m.opts.BumpPercent), suggestedTip)
+	feeCap := bumpFee(gasFeeCap, m.opts.BumpPercent)
+	if head.BaseFee != nil {
+		feeCap = bigMax(feeCap, new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(basefeeWiggleMultiplier))))
+	}
+	feeCap = bigMax(feeCap, tip)
+
+	return tip, feeCap, nil
+}
+
+// waitForAny waits until one of hashes is mined or timeout elapses, returning a nil
+// receipt on timeout.
+func (m *TxMonitor) waitForAny(parent context.Context, hashes []common.Hash, timeout time.Duration) (*types.Receipt, error) {
+	parent = ensureContext(parent)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	receipt, err := m.helper.waitMined(ctx, hashes, m.opts.Confirmations)
+	if errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+		return nil, nil
+	}
+
+	return receipt, err
+}
+
+func (m *TxMonitor) result(receipt *types.Receipt, submitted []common.Hash) *TxMonitorResult {
+	return &TxMonitorResult{
+		Hash:      receipt.TxHash,
+		Receipt:   receipt,
+		Submitted: submitted,
+	}
+}
+
+// bumpFee increases fee by percent, rounding up and by at least 1 wei so the
+// replacement rule is always met.
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	return bigMax(bumped, new(big.Int).Add(fee, big.NewInt(1)))
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
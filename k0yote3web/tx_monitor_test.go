@@ -0,0 +1,218 @@
+package k0yote3web
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumpFee(t *testing.T) {
+	assert.Equal(t, big.NewInt(110), bumpFee(big.NewInt(100), 10))
+	// rounds up so that the replacement is always at least 10% higher
+	assert.Equal(t, big.NewInt(13), bumpFee(big.NewInt(11), 10))
+	assert.Equal(t, big.NewInt(1), bumpFee(big.NewInt(0), 10))
+}
+
+// stubMonitorChain is a stubWalletChain mining the transactions sent from the minedFrom-th on.
+// With nonceTooLow, the first transaction is mined as soon as a replacement is sent.
+type stubMonitorChain struct {
+	stubWalletChain
+	minedFrom   int
+	nonceTooLow bool
+}
+
+func (s *stubMonitorChain) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	if s.nonceTooLow && len(s.sent) > 0 {
+		s.minedFrom = 0
+		return common.Hash{}, errors.New("nonce too low: next nonce 8, tx nonce 7")
+	}
+	return s.stubWalletChain.SendRawTransaction(raw)
+}
+
+func (s *stubMonitorChain) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	for i, tx := range s.sent {
+		if tx.Hash() == hash && i >= s.minedFrom {
+			return &types.Receipt{
+				Status:      types.ReceiptStatusSuccessful,
+				TxHash:      hash,
+				BlockNumber: big.NewInt(1),
+				Logs:        []*types.Log{},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubMonitorChain) BlockNumber() hexutil.Uint64 {
+	return 1
+}
+
+func newStubTxMonitor(t *testing.T, stub *stubMonitorChain, opts *TxMonitorOptions) (*TxMonitor, *bind.TransactOpts) {
+	helper := newStubContractHelper(t, stub)
+	assert.NoError(t, helper.UpdatePrivateKey("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"))
+
+	monitor, err := newTxMonitor(helper.ProviderHandler, opts)
+	assert.NoError(t, err)
+	transactOpts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	return monitor, transactOpts
+}
+
+func TestTxMonitorWaitSweep(t *testing.T) {
+	stub := &stubMonitorChain{
+		stubWalletChain: stubWalletChain{chainID: 10, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000), l1Fee: big.NewInt(1000)},
+		minedFrom:       1,
+	}
+	monitor, opts := newStubTxMonitor(t, stub, &TxMonitorOptions{ReplaceAfter: 50 * time.Millisecond})
+	wallet, err := newWallet(monitor.helper.ProviderHandler)
+	assert.NoError(t, err)
+
+	tx, err := wallet.Sweep(opts, common.HexToAddress("0x1"))
+	assert.NoError(t, err)
+
+	result, err := monitor.WaitSweep(opts, tx)
+	assert.NoError(t, err)
+	assert.Len(t, stub.sent, 2)
+	assert.Equal(t, stub.sent[1].Hash(), result.Hash)
+	assert.Equal(t, []common.Hash{tx.Hash(), result.Hash}, result.Submitted)
+
+	replacement := stub.sent[1]
+	assert.Equal(t, tx.Nonce(), replacement.Nonce())
+	assert.Equal(t, bumpFee(tx.GasFeeCap(), 10), replacement.GasFeeCap())
+	// the balance covers the value and the bumped fees
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(replacement.Gas()), replacement.GasFeeCap())
+	cost.Add(cost, replacement.Value())
+	assert.Equal(t, big.NewInt(10_000_000-1100), cost)
+}
+
+func TestTxMonitorWaitReplaces(t *testing.T) {
+	stub := &stubMonitorChain{
+		stubWalletChain: stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000)},
+		minedFrom:       2,
+	}
+	monitor, opts := newStubTxMonitor(t, stub, &TxMonitorOptions{ReplaceAfter: 50 * time.Millisecond})
+	wallet, err := newWallet(monitor.helper.ProviderHandler)
+	assert.NoError(t, err)
+
+	tx, err := wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+	assert.NoError(t, err)
+
+	result, err := monitor.Wait(opts, tx)
+	assert.NoError(t, err)
+	assert.Len(t, stub.sent, 3)
+	assert.Equal(t, stub.sent[2].Hash(), result.Hash)
+	assert.Equal(t, []common.Hash{stub.sent[0].Hash(), stub.sent[1].Hash(), stub.sent[2].Hash()}, result.Submitted)
+	for i := 1; i < len(stub.sent); i++ {
+		assert.Equal(t, tx.Nonce(), stub.sent[i].Nonce())
+		assert.Equal(t, tx.Value(), stub.sent[i].Value())
+		assert.Equal(t, bumpFee(stub.sent[i-1].GasFeeCap(), 10), stub.sent[i].GasFeeCap())
+	}
+}
+
+func TestTxMonitorWaitMaxReplacements(t *testing.T) {
+	stub := &stubMonitorChain{
+		stubWalletChain: stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000)},
+		minedFrom:       100,
+	}
+	monitor, opts := newStubTxMonitor(t, stub, &TxMonitorOptions{ReplaceAfter: 20 * time.Millisecond, MaxReplacements: 2})
+	wallet, err := newWallet(monitor.helper.ProviderHandler)
+	assert.NoError(t, err)
+
+	tx, err := wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+	assert.NoError(t, err)
+
+	_, err = monitor.Wait(opts, tx)
+	assert.ErrorContains(t, err, "transaction with nonce 7 was not mined after 2 replacements")
+	assert.Len(t, stub.sent, 3)
+}
+
+func TestTxMonitorWaitAlreadyMined(t *testing.T) {
+	stub := &stubMonitorChain{
+		stubWalletChain: stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000)},
+		minedFrom:       100,
+		nonceTooLow:     true,
+	}
+	monitor, opts := newStubTxMonitor(t, stub, &TxMonitorOptions{ReplaceAfter: 50 * time.Millisecond})
+	wallet, err := newWallet(monitor.helper.ProviderHandler)
+	assert.NoError(t, err)
+
+	tx, err := wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+	assert.NoError(t, err)
+
+	// the replacement is rejected as the transaction was mined meanwhile
+	result, err := monitor.Wait(opts, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash(), result.Hash)
+	assert.Equal(t, []common.Hash{tx.Hash()}, result.Submitted)
+	assert.Len(t, stub.sent, 1)
+}
+
+func TestTxMonitorSpeedUpAndCancel(t *testing.T) {
+	stub := &stubMonitorChain{
+		stubWalletChain: stubWalletChain{chainID: 1337, balance: big.NewInt(10_000_000)},
+	}
+	monitor, opts := newStubTxMonitor(t, stub, &TxMonitorOptions{BumpPercent: 20})
+	wallet, err := newWallet(monitor.helper.ProviderHandler)
+	assert.NoError(t, err)
+
+	// a legacy transaction on a chain without base fee
+	tx, err := wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.LegacyTxType), tx.Type())
+
+	spedUp, err := monitor.SpeedUp(opts, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.LegacyTxType), spedUp.Type())
+	assert.Equal(t, tx.Nonce(), spedUp.Nonce())
+	assert.Equal(t, tx.To(), spedUp.To())
+	assert.Equal(t, tx.Value(), spedUp.Value())
+	assert.Equal(t, tx.Gas(), spedUp.Gas())
+	assert.Equal(t, big.NewInt(60), spedUp.GasPrice())
+
+	cancel, err := monitor.Cancel(opts, spedUp)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Nonce(), cancel.Nonce())
+	assert.Equal(t, opts.From, *cancel.To())
+	assert.Equal(t, 0, cancel.Value().Sign())
+	assert.Empty(t, cancel.Data())
+	assert.Equal(t, uint64(21_000), cancel.Gas())
+	assert.Equal(t, big.NewInt(72), cancel.GasPrice())
+
+	assert.Len(t, stub.sent, 3)
+	assert.Equal(t, map[uint64]common.Hash{tx.Nonce(): cancel.Hash()}, monitor.helper.nonces.InFlight(opts.From))
+}
+
+func TestTxMonitorReplaceOtherSender(t *testing.T) {
+	stub := &stubMonitorChain{
+		stubWalletChain: stubWalletChain{chainID: 1337, balance: big.NewInt(10_000_000)},
+	}
+	monitor, opts := newStubTxMonitor(t, stub, nil)
+
+	key, err := crypto.HexToECDSA("59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d")
+	assert.NoError(t, err)
+	to := common.HexToAddress("0x1")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)), &types.LegacyTx{
+		Nonce:    3,
+		GasPrice: big.NewInt(50),
+		Gas:      21_000,
+		To:       &to,
+		Value:    big.NewInt(1000),
+	})
+	assert.NoError(t, err)
+
+	_, err = monitor.SpeedUp(opts, tx)
+	assert.ErrorContains(t, err, "not from "+opts.From.Hex())
+	_, err = monitor.Cancel(opts, tx)
+	assert.ErrorContains(t, err, "not from "+opts.From.Hex())
+	assert.Empty(t, stub.sent)
+}
@@ -1,16 +1,50 @@
 package k0yote3web
 
+import "net/http"
+
 type SDKOptions struct {
 	ThirdpartyProvier ThirdpartyProvider
 	PrivateKey        string
 
+	// KeystorePath is an encrypted (V3 JSON) keystore file, decrypted with KeystorePassphrase
+	KeystorePath       string
+	KeystorePassphrase string
+
+	// Mnemonic is a BIP-39 phrase, derived with DerivationPath (default m/44'/60'/0'/0/0)
+	Mnemonic           string
+	MnemonicPassphrase string
+	DerivationPath     string
+
 	ApiKey string
+	// QuickNodeEndpoint is the name of the QuickNode endpoint, the first label of its host
+	QuickNodeEndpoint string
+	// Headers are sent with every RPC request, e.g. a bearer token for a private node
+	Headers http.Header
+
+	// DryRun simulates every write at the pending block and logs a report instead of broadcasting it
+	DryRun bool
+	// GasStrategy prices transactions from the fee history instead of the node suggestion
+	GasStrategy *GasStrategyOptions
+
+	// FailoverRpcUrls are more endpoints of the same chain, requests are spread over them
+	// and the primary one and fail over when an endpoint is unhealthy
+	FailoverRpcUrls []string
 }
 
 type DownloadMetaOptions struct {
 	BaseURL      string
 	StartTokenID int
 	EndTokenID   int
+	// ContractAddress discovers the metadata URLs from tokenURI of an ERC-721 contract instead of BaseURL
+	ContractAddress string
+	// IpfsGateways resolve ipfs:// uris in order until one succeeds, {cid} marks a subdomain
+	// gateway such as https://{cid}.ipfs.dweb.link/
+	IpfsGateways []string
+	// ArweaveGateways resolve ar:// uris in order until one succeeds
+	ArweaveGateways []string
+	// IpfsNode is a kubo RPC endpoint ipfs:// uris are read from before the gateways, the
+	// content is pinned on the node when Pin is set
+	IpfsNode *IPFSOptions
 }
 
 type DownloadCh struct {
@@ -22,6 +56,8 @@ type Attribute struct {
 	DisplayType string `json:"display_type,omitempty"`
 	TraitType   string `json:"trait_type"`
 	Value       any    `json:"value"`
+	// MaxValue is the upper bound of a numeric trait, shown by OpenSea as "value of max_value"
+	MaxValue any `json:"max_value,omitempty"`
 }
 
 type MetaData struct {
@@ -29,15 +65,30 @@ type MetaData struct {
 	Image       string      `json:"image"`
 	Description string      `json:"description,omitempty"`
 	Attributes  []Attribute `json:"attributes"`
+
+	ExternalURL     string `json:"external_url,omitempty"`
+	AnimationURL    string `json:"animation_url,omitempty"`
+	YoutubeURL      string `json:"youtube_url,omitempty"`
+	BackgroundColor string `json:"background_color,omitempty"`
+	// Properties are the arbitrary ERC-1155 properties
+	Properties map[string]any `json:"properties,omitempty"`
 }
 
 type ThirdpartyProvider string
 
 const (
-	INFURA  ThirdpartyProvider = "infura"
-	ALCHEMY ThirdpartyProvider = "alchemy"
+	INFURA    ThirdpartyProvider = "infura"
+	ALCHEMY   ThirdpartyProvider = "alchemy"
+	QUICKNODE ThirdpartyProvider = "quicknode"
+	ANKR      ThirdpartyProvider = "ankr"
+	// PUBLIC uses the public RPC url of a registered chain
+	PUBLIC ThirdpartyProvider = "public"
+	LOCAL  ThirdpartyProvider = "local"
 )
 
+// GasPriority multiplies the base fee of a transaction.
+//
+// Deprecated: price transactions with a GasSpeed of GasStrategyOptions instead.
 type GasPriority float64
 
 const (
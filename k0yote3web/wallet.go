@@ -0,0 +1,48 @@
+package k0yote3web
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Wallet sends the native currency of the chain.
+type Wallet struct {
+	helper *contractHelper
+}
+
+func newWallet(handler *ProviderHandler) (*Wallet, error) {
+	helper, err := newContractHelper(handler)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		helper: helper,
+	}, nil
+}
+
+// Transfer sends amount wei to the address.
+func (w *Wallet) Transfer(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %v", amount)
+	}
+
+	transfer := *opts
+	transfer.Value = amount
+
+	return w.helper.transact(&transfer, &to, nil)
+}
+
+// Sweep sends the whole balance, less the fees, to the address.
+func (w *Wallet) Sweep(opts *bind.TransactOpts, to common.Address) (*types.Transaction, error) {
+	rawTx, err := w.helper.createRawTransaction(opts, &to)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.helper.sendRawTransaction(opts, rawTx)
+}
@@ -0,0 +1,123 @@
+package k0yote3web
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubWalletChain is an account holding balance on chainID, an OP stack chain when it is 10.
+type stubWalletChain struct {
+	chainID int64
+	baseFee *big.Int
+	balance *big.Int
+	l1Fee   *big.Int
+	// revert is the revert data of gas estimations when set
+	revert string
+	sent   []*types.Transaction
+}
+
+func (s *stubWalletChain) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(s.chainID))
+}
+
+func (s *stubWalletChain) GetBalance(account common.Address, block string) *hexutil.Big {
+	return (*hexutil.Big)(s.balance)
+}
+
+func (s *stubWalletChain) EstimateGas(args callArgs) (hexutil.Uint64, error) {
+	if s.revert != "" {
+		return 0, stubRevertError{data: s.revert}
+	}
+	return 21_000, nil
+}
+
+func (s *stubWalletChain) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{Number: big.NewInt(1), Difficulty: new(big.Int), BaseFee: s.baseFee}
+}
+
+func (s *stubWalletChain) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(2))
+}
+
+func (s *stubWalletChain) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(50))
+}
+
+func (s *stubWalletChain) Call(args callArgs, block string) hexutil.Bytes {
+	return common.LeftPadBytes(s.l1Fee.Bytes(), 32)
+}
+
+func (s *stubWalletChain) GetTransactionCount(account common.Address, block string) hexutil.Uint64 {
+	return 7
+}
+
+func (s *stubWalletChain) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	s.sent = append(s.sent, tx)
+	return tx.Hash(), nil
+}
+
+func newStubWallet(t *testing.T, stub *stubWalletChain) (*Wallet, *contractHelper) {
+	helper := newStubContractHelper(t, stub)
+	assert.NoError(t, helper.UpdatePrivateKey("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"))
+
+	wallet, err := newWallet(helper.ProviderHandler)
+	assert.NoError(t, err)
+
+	return wallet, helper
+}
+
+func TestWalletSweepOPStack(t *testing.T) {
+	stub := &stubWalletChain{chainID: 10, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000), l1Fee: big.NewInt(1000)}
+	wallet, helper := newStubWallet(t, stub)
+	opts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	tx, err := wallet.Sweep(opts, common.HexToAddress("0x1"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	assert.Equal(t, uint64(7), tx.Nonce())
+	// balance - 21000 * (2 + 2*100) - 1000 * 110%
+	assert.Equal(t, big.NewInt(10_000_000-21_000*202-1100), tx.Value())
+	assert.Len(t, stub.sent, 1)
+}
+
+func TestWalletSweepLegacy(t *testing.T) {
+	stub := &stubWalletChain{chainID: 1337, balance: big.NewInt(10_000_000)}
+	wallet, helper := newStubWallet(t, stub)
+	opts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	tx, err := wallet.Sweep(opts, common.HexToAddress("0x1"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.LegacyTxType), tx.Type())
+	assert.Equal(t, big.NewInt(10_000_000-21_000*50), tx.Value())
+
+	stub.balance = big.NewInt(1000)
+	_, err = wallet.Sweep(opts, common.HexToAddress("0x1"))
+	assert.ErrorContains(t, err, "not enough fund")
+}
+
+func TestWalletTransfer(t *testing.T) {
+	stub := &stubWalletChain{chainID: 1337, baseFee: big.NewInt(100), balance: big.NewInt(10_000_000)}
+	wallet, helper := newStubWallet(t, stub)
+	opts, err := helper.GetTransactOpts(context.Background())
+	assert.NoError(t, err)
+
+	tx, err := wallet.Transfer(opts, common.HexToAddress("0x1"), ToWeiR("0.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, ToWeiR("0.5"), tx.Value())
+	assert.Equal(t, uint64(21_000), tx.Gas())
+
+	_, err = wallet.Transfer(opts, common.HexToAddress("0x1"), big.NewInt(0))
+	assert.ErrorContains(t, err, "invalid amount")
+}